@@ -0,0 +1,184 @@
+/*
+Copyright 2018 Comcast Cable Communications Management, LLC
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vinyldns
+
+import (
+	"sync"
+	"time"
+
+	"github.com/vinyldns/go-vinyldns/vinyldns"
+)
+
+// Config is the provider's meta: the configured VinylDNS client along with
+// the provider-level settings consulted by resources and data sources.
+type Config struct {
+	Client *vinyldns.Client
+
+	// MaxConcurrency bounds how many API calls a single operation may have
+	// in flight at once when it fans out across many resources.
+	MaxConcurrency int
+
+	// RecordPollDelay is how long to wait after submitting a record set
+	// change before first polling for its status.
+	RecordPollDelay time.Duration
+
+	// RecordPollMinTimeout is the shortest wait between polls for the status
+	// of a record set or batch change.
+	RecordPollMinTimeout time.Duration
+
+	// DefaultZoneEmail is the email address given to zones configured
+	// without one of their own.
+	DefaultZoneEmail string
+
+	// DefaultZoneID is the zone of record sets configured without a
+	// zone_id of their own.
+	DefaultZoneID string
+
+	// CNAMEAutoTrailingDot appends the trailing '.' to cname targets
+	// written without one, rather than rejecting them.
+	CNAMEAutoTrailingDot bool
+
+	// NSPTRAutoTrailingDot appends the trailing '.' to NS name servers and
+	// PTR names written without one.
+	NSPTRAutoTrailingDot bool
+
+	// VerifyDeletePermission checks the provider's credentials may delete a
+	// record set before its deletion is submitted.
+	VerifyDeletePermission bool
+
+	// MaxRecordSetEntries is the most records a single record set may be
+	// given; zero means no limit.
+	MaxRecordSetEntries int
+
+	// Metrics counts the provider's work over a run, when a metrics file
+	// is configured; otherwise it's nil.
+	Metrics *providerMetrics
+
+	// memberOf holds the IDs of the groups the provider's credentials are
+	// in, listed once per run by memberGroupIDs.
+	memberOfMu sync.Mutex
+	memberOf   map[string]bool
+
+	// planZones and planRecordSets hold, by zone ID, the zones and record
+	// sets plans check record sets against, read once per run by planZone
+	// and planZoneRecordSets.
+	planMu         sync.Mutex
+	planZones      map[string]vinyldns.Zone
+	planRecordSets map[string][]vinyldns.RecordSet
+}
+
+// planZone returns the zone, read the first time a plan asks for it and kept
+// for the rest of the run, so that planning many record sets in one zone
+// doesn't read it for each. Only plan-time checks should use it; anything
+// that must see the zone as it is now reads it from the client.
+func (c *Config) planZone(zoneID string) (vinyldns.Zone, error) {
+	c.planMu.Lock()
+	defer c.planMu.Unlock()
+
+	if z, ok := c.planZones[zoneID]; ok {
+		return z, nil
+	}
+
+	z, err := c.Client.Zone(zoneID)
+	if err != nil {
+		return z, err
+	}
+
+	if c.planZones == nil {
+		c.planZones = map[string]vinyldns.Zone{}
+	}
+	c.planZones[zoneID] = z
+
+	return z, nil
+}
+
+// planZoneRecordSets returns every record set in the zone, listed the first
+// time a plan asks for them and kept for the rest of the run, like planZone,
+// so that the checks listing a zone's record sets list each zone once.
+func (c *Config) planZoneRecordSets(zoneID string) ([]vinyldns.RecordSet, error) {
+	c.planMu.Lock()
+	defer c.planMu.Unlock()
+
+	if rss, ok := c.planRecordSets[zoneID]; ok {
+		return rss, nil
+	}
+
+	rss, err := c.Client.RecordSetsListAll(zoneID, vinyldns.ListFilter{})
+	if err != nil {
+		return nil, err
+	}
+
+	if c.planRecordSets == nil {
+		c.planRecordSets = map[string][]vinyldns.RecordSet{}
+	}
+	c.planRecordSets[zoneID] = rss
+
+	return rss, nil
+}
+
+// memberGroupIDs returns the IDs of the groups the provider's credentials are
+// in. They're listed the first time they're asked for and kept for the rest
+// of the run, so that deleting many record sets doesn't list them for each.
+func (c *Config) memberGroupIDs() (map[string]bool, error) {
+	c.memberOfMu.Lock()
+	defer c.memberOfMu.Unlock()
+
+	if c.memberOf != nil {
+		return c.memberOf, nil
+	}
+
+	groups, err := c.Client.GroupsListAll(vinyldns.ListFilter{})
+	if err != nil {
+		return nil, err
+	}
+
+	memberOf := map[string]bool{}
+	for _, g := range groups {
+		memberOf[g.ID] = true
+	}
+	c.memberOf = memberOf
+
+	return memberOf, nil
+}
+
+// forEach calls fn once for each index in [0, n), running no more than
+// MaxConcurrency calls at a time. It waits for every call to finish and
+// returns the first error encountered, if any.
+func (c *Config) forEach(n int, fn func(i int) error) error {
+	limit := c.MaxConcurrency
+	if limit < 1 {
+		limit = 1
+	}
+
+	sem := make(chan struct{}, limit)
+	errs := make(chan error, n)
+	var wg sync.WaitGroup
+
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			if err := fn(i); err != nil {
+				errs <- err
+			}
+		}(i)
+	}
+
+	wg.Wait()
+	close(errs)
+
+	return <-errs
+}
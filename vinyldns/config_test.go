@@ -0,0 +1,109 @@
+/*
+Copyright 2018 Comcast Cable Communications Management, LLC
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vinyldns
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestConfigForEachBoundsConcurrency(t *testing.T) {
+	c := &Config{MaxConcurrency: 3}
+
+	var mu sync.Mutex
+	inFlight, peak, calls := 0, 0, 0
+
+	err := c.forEach(20, func(i int) error {
+		mu.Lock()
+		inFlight++
+		calls++
+		if inFlight > peak {
+			peak = inFlight
+		}
+		mu.Unlock()
+
+		time.Sleep(5 * time.Millisecond)
+
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if calls != 20 {
+		t.Errorf("expected 20 calls; got %d", calls)
+	}
+
+	if peak > 3 {
+		t.Errorf("expected at most 3 concurrent calls; got %d", peak)
+	}
+}
+
+func TestConfigForEachReturnsError(t *testing.T) {
+	c := &Config{MaxConcurrency: 2}
+	expected := errors.New("boom")
+
+	err := c.forEach(5, func(i int) error {
+		if i == 3 {
+			return expected
+		}
+
+		return nil
+	})
+	if err != expected {
+		t.Errorf("expected %v; got %v", expected, err)
+	}
+}
+
+func TestConfigPlanZoneReadsOnce(t *testing.T) {
+	requests := map[string]int{}
+	meta, closeServer := testMeta(func(w http.ResponseWriter, r *http.Request) {
+		requests[r.URL.Path]++
+		switch r.URL.Path {
+		case "/zones/zone-id":
+			w.Write([]byte(`{"zone":{"id":"zone-id","name":"example.com."}}`))
+		case "/zones/zone-id/recordsets":
+			w.Write([]byte(`{"recordSets":[{"id":"rs-id","name":"web","type":"A"}]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	defer closeServer()
+
+	for i := 0; i < 3; i++ {
+		if z, err := meta.planZone("zone-id"); err != nil || z.Name != "example.com." {
+			t.Fatalf("expected zone example.com.; got %#v, %v", z, err)
+		}
+		if rss, err := meta.planZoneRecordSets("zone-id"); err != nil || len(rss) != 1 {
+			t.Fatalf("expected the zone's record set; got %#v, %v", rss, err)
+		}
+	}
+
+	if requests["/zones/zone-id"] != 1 || requests["/zones/zone-id/recordsets"] != 1 {
+		t.Errorf("expected the zone and its record sets to be read once each; got %v", requests)
+	}
+
+	// a zone that can't be read is tried again
+	meta.planZone("missing-zone-id")
+	meta.planZone("missing-zone-id")
+	if requests["/zones/missing-zone-id"] != 2 {
+		t.Errorf("expected a failed read not to be kept; got %v", requests)
+	}
+}
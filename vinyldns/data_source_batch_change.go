@@ -0,0 +1,129 @@
+/*
+Copyright 2018 Comcast Cable Communications Management, LLC
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vinyldns
+
+import (
+	"log"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func dataSourceVinylDNSBatchChange() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceVinylDNSBatchChangeRead,
+
+		Schema: map[string]*schema.Schema{
+			"batch_change_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"status": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"comments": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"user_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"user_name": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"owner_group_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"created": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"changes": &schema.Schema{
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"change_type": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"input_name": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"type": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"zone_id": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"record_set_id": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"status": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"system_message": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceVinylDNSBatchChangeRead(d *schema.ResourceData, meta interface{}) error {
+	id := d.Get("batch_change_id").(string)
+	log.Printf("[INFO] Reading vinyldns batch change: %s", id)
+	b, err := meta.(*Config).Client.BatchRecordChange(id)
+	if err != nil {
+		return err
+	}
+
+	changes := []map[string]interface{}{}
+	for _, c := range b.Changes {
+		changes = append(changes, map[string]interface{}{
+			"id":             c.ID,
+			"change_type":    c.ChangeType,
+			"input_name":     c.InputName,
+			"type":           c.Type,
+			"zone_id":        c.ZoneID,
+			"record_set_id":  c.RecordSetID,
+			"status":         c.Status,
+			"system_message": c.SystemMessage,
+		})
+	}
+
+	d.SetId(b.ID)
+	d.Set("status", b.Status)
+	d.Set("comments", b.Comments)
+	d.Set("user_id", b.UserID)
+	d.Set("user_name", b.UserName)
+	d.Set("owner_group_id", b.OwnerGroupID)
+	d.Set("created", b.CreatedTimestamp)
+
+	return d.Set("changes", changes)
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2018 Comcast Cable Communications Management, LLC
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vinyldns
+
+import (
+	"log"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func dataSourceVinylDNSGroupMembers() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceVinylDNSGroupMembersRead,
+
+		Schema: map[string]*schema.Schema{
+			"group_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"name": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"member": computedUserSchema(),
+			"admin":  computedUserSchema(),
+		},
+	}
+}
+
+func dataSourceVinylDNSGroupMembersRead(d *schema.ResourceData, meta interface{}) error {
+	groupID := d.Get("group_id").(string)
+	log.Printf("[INFO] Reading vinyldns group members: %s", groupID)
+	g, err := meta.(*Config).Client.Group(groupID)
+	if err != nil {
+		return err
+	}
+
+	d.SetId(g.ID)
+	d.Set("name", g.Name)
+
+	if err := d.Set("member", flattenUsers(nil, g.Members)); err != nil {
+		return err
+	}
+
+	return d.Set("admin", flattenUsers(nil, g.Admins))
+}
+
+// computedUserSchema is userSchema as read from vinyldns, with the same
+// attributes so that users can be written back as vinyldns_group blocks.
+func computedUserSchema() *schema.Schema {
+	fields := map[string]*schema.Schema{}
+	for k := range userSchema().Elem.(*schema.Resource).Schema {
+		fields[k] = &schema.Schema{
+			Type:     schema.TypeString,
+			Computed: true,
+		}
+	}
+
+	return &schema.Schema{
+		Type:     schema.TypeList,
+		Computed: true,
+		Elem:     &schema.Resource{Schema: fields},
+	}
+}
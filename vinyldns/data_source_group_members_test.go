@@ -0,0 +1,38 @@
+/*
+Copyright 2018 Comcast Cable Communications Management, LLC
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vinyldns
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/vinyldns/go-vinyldns/vinyldns"
+)
+
+func TestComputedUserSchema(t *testing.T) {
+	user := userSchema().Elem.(*schema.Resource).Schema
+	computed := computedUserSchema().Elem.(*schema.Resource).Schema
+
+	if len(computed) != len(user) {
+		t.Fatalf("expected the %d attributes of a vinyldns_group user; got %d", len(user), len(computed))
+	}
+
+	// every flattened attribute is in the schema
+	for _, u := range flattenUsers(nil, []vinyldns.User{{ID: "user-id", UserName: "jdoe"}}) {
+		for k := range u.(map[string]interface{}) {
+			if s, ok := computed[k]; !ok || !s.Computed {
+				t.Errorf("attribute %s isn't a computed attribute of the schema", k)
+			}
+		}
+	}
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2018 Comcast Cable Communications Management, LLC
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vinyldns
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/vinyldns/go-vinyldns/vinyldns"
+)
+
+func dataSourceVinylDNSRecordByFQDN() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceVinylDNSRecordByFQDNRead,
+
+		Schema: map[string]*schema.Schema{
+			"fqdn": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"type": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"zone_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"zone_name": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"name": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"ttl": &schema.Schema{
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"owner_group_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"records_json": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"records": &schema.Schema{
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     recordResource(),
+			},
+		},
+	}
+}
+
+func dataSourceVinylDNSRecordByFQDNRead(d *schema.ResourceData, meta interface{}) error {
+	fqdn := d.Get("fqdn").(string)
+	recordType := d.Get("type").(string)
+	log.Printf("[INFO] Reading vinyldns record set by fqdn; fqdn=%s type=%s", fqdn, recordType)
+	client := meta.(*Config).Client
+
+	zones, err := client.ZonesListAll(vinyldns.ListFilter{})
+	if err != nil {
+		return err
+	}
+
+	z, name, ok := zoneForFQDN(zones, fqdn)
+	if !ok {
+		return fmt.Errorf("no vinyldns zone visible to the provider contains %s", fqdn)
+	}
+
+	// vinyldns may name the apex for the zone or @, so list it unfiltered
+	filter := vinyldns.ListFilter{}
+	if !isApex(name, z.Name) {
+		filter.NameFilter = name
+	}
+
+	rss, err := client.RecordSetsListAll(z.ID, filter)
+	if err != nil {
+		return err
+	}
+
+	for _, rs := range rss {
+		named := strings.EqualFold(rs.Name, name) || (isApex(name, z.Name) && isApex(rs.Name, z.Name))
+		if rs.Type != recordType || !named {
+			continue
+		}
+
+		d.SetId(rs.ID)
+		d.Set("zone_id", z.ID)
+		d.Set("zone_name", z.Name)
+		d.Set("name", rs.Name)
+		d.Set("ttl", rs.TTL)
+		d.Set("owner_group_id", rs.OwnerGroupID)
+		d.Set("records_json", recordsString(rs.Records))
+
+		return d.Set("records", flattenRecords(rs.Records))
+	}
+
+	return fmt.Errorf("zone %s has no %s record set named %s", z.Name, recordType, name)
+}
+
+// zoneForFQDN returns the zone of zones that fqdn falls within, the one with
+// the longest name should zones be delegated from one another, and fqdn's name
+// relative to it. The zone's apex is named for the zone itself.
+func zoneForFQDN(zones []vinyldns.Zone, fqdn string) (vinyldns.Zone, string, bool) {
+	fqdn = strings.ToLower(strings.TrimSuffix(fqdn, "."))
+
+	var match vinyldns.Zone
+	name := ""
+	longest := -1
+	for _, z := range zones {
+		zoneName := strings.ToLower(strings.TrimSuffix(z.Name, "."))
+		if len(zoneName) <= longest {
+			continue
+		}
+
+		if fqdn == zoneName {
+			match, name, longest = z, z.Name, len(zoneName)
+		} else if strings.HasSuffix(fqdn, "."+zoneName) {
+			match, name, longest = z, strings.TrimSuffix(fqdn, "."+zoneName), len(zoneName)
+		}
+	}
+
+	return match, name, longest >= 0
+}
@@ -0,0 +1,54 @@
+/*
+Copyright 2018 Comcast Cable Communications Management, LLC
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vinyldns
+
+import (
+	"testing"
+
+	"github.com/vinyldns/go-vinyldns/vinyldns"
+)
+
+func TestZoneForFQDN(t *testing.T) {
+	zones := []vinyldns.Zone{
+		{ID: "example", Name: "example.com."},
+		{ID: "dev", Name: "dev.example.com."},
+		{ID: "other", Name: "other.com."},
+	}
+
+	cases := []struct {
+		fqdn string
+		zone string
+		name string
+	}{
+		{"www.example.com.", "example", "www"},
+		{"www.example.com", "example", "www"},
+		{"WWW.Example.COM.", "example", "www"},
+		{"api.dev.example.com.", "dev", "api"},
+		{"a.b.dev.example.com.", "dev", "a.b"},
+		{"dev.example.com.", "dev", "dev.example.com."},
+		{"example.com.", "example", "example.com."},
+	}
+
+	for _, c := range cases {
+		z, name, ok := zoneForFQDN(zones, c.fqdn)
+		if !ok || z.ID != c.zone || name != c.name {
+			t.Errorf("zoneForFQDN(%q) = %s, %q, %t; expected %s, %q", c.fqdn, z.ID, name, ok, c.zone, c.name)
+		}
+	}
+
+	for _, fqdn := range []string{"www.example.org.", "notexample.com.", "com."} {
+		if z, _, ok := zoneForFQDN(zones, fqdn); ok {
+			t.Errorf("expected no zone for %s; got %s", fqdn, z.ID)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2018 Comcast Cable Communications Management, LLC
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vinyldns
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/vinyldns/go-vinyldns/vinyldns"
+)
+
+func dataSourceVinylDNSRecordSetChanges() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceVinylDNSRecordSetChangesRead,
+
+		Schema: map[string]*schema.Schema{
+			"zone_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"record_set_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"max_items": &schema.Schema{
+				Type:     schema.TypeInt,
+				Optional: true,
+				Default:  100,
+			},
+			"changes": &schema.Schema{
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"change_type": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"status": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"user_id": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"created": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceVinylDNSRecordSetChangesRead(d *schema.ResourceData, meta interface{}) error {
+	zoneID := d.Get("zone_id").(string)
+	recordSetID := d.Get("record_set_id").(string)
+	maxItems := d.Get("max_items").(int)
+	log.Printf("[INFO] Reading vinyldns record set changes: %s", recordSetID)
+
+	changes := []map[string]interface{}{}
+	filter := vinyldns.ListFilter{
+		MaxItems: 100,
+	}
+
+	// vinyldns lists changes per zone, newest first, so page through the zone's
+	// changes until enough belonging to the record set have been collected
+	for len(changes) < maxItems {
+		page, err := meta.(*Config).Client.RecordSetChanges(zoneID, filter)
+		if err != nil {
+			return err
+		}
+
+		for _, c := range page.RecordSetChanges {
+			if c.RecordSet.ID != recordSetID {
+				continue
+			}
+
+			changes = append(changes, map[string]interface{}{
+				"id":          c.ID,
+				"change_type": c.ChangeType,
+				"status":      c.Status,
+				"user_id":     c.UserID,
+				"created":     c.Created,
+			})
+
+			if len(changes) == maxItems {
+				break
+			}
+		}
+
+		if page.NextID == "" {
+			break
+		}
+
+		filter.StartFrom = page.NextID
+	}
+
+	d.SetId(fmt.Sprintf("%s:%s", zoneID, recordSetID))
+
+	return d.Set("changes", changes)
+}
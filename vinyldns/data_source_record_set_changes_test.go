@@ -0,0 +1,69 @@
+/*
+Copyright 2018 Comcast Cable Communications Management, LLC
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vinyldns
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+)
+
+func TestAccVinylDNSRecordSetChangesDataSourceBasic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccVinylDNSRecordSetDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccVinylDNSRecordSetChangesDataSourceConfigBasic,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.vinyldns_record_set_changes.test", "changes.#", "1"),
+					resource.TestCheckResourceAttr("data.vinyldns_record_set_changes.test", "changes.0.change_type", "Create"),
+					resource.TestCheckResourceAttr("data.vinyldns_record_set_changes.test", "changes.0.status", "Complete"),
+				),
+			},
+		},
+	})
+}
+
+const testAccVinylDNSRecordSetChangesDataSourceConfigBasic = `
+resource "vinyldns_group" "test_group" {
+	name = "terraformtestgroup"
+	description = "some description"
+	email = "tftest@tf.com"
+}
+
+resource "vinyldns_zone" "test_zone" {
+	name = "system-test."
+	email = "foo@bar.com"
+	admin_group_id = "${vinyldns_group.test_group.id}"
+	depends_on = [
+		"vinyldns_group.test_group"
+	]
+}
+
+resource "vinyldns_record_set" "test_a_record_set" {
+	name = "terraformtestrecordset"
+	zone_id = "${vinyldns_zone.test_zone.id}"
+	type = "A"
+	ttl = 6000
+	record_addresses = ["127.0.0.1"]
+	depends_on = [
+		"vinyldns_zone.test_zone"
+	]
+}
+
+data "vinyldns_record_set_changes" "test" {
+	zone_id = "${vinyldns_zone.test_zone.id}"
+	record_set_id = "${vinyldns_record_set.test_a_record_set.id}"
+}`
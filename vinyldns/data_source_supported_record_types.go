@@ -0,0 +1,37 @@
+/*
+Copyright 2018 Comcast Cable Communications Management, LLC
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vinyldns
+
+import (
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func dataSourceVinylDNSSupportedRecordTypes() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceVinylDNSSupportedRecordTypesRead,
+
+		Schema: map[string]*schema.Schema{
+			"types": &schema.Schema{
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+func dataSourceVinylDNSSupportedRecordTypesRead(d *schema.ResourceData, meta interface{}) error {
+	d.SetId("supported_record_types")
+
+	return d.Set("types", SupportedRecordTypes())
+}
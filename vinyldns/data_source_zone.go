@@ -0,0 +1,177 @@
+/*
+Copyright 2018 Comcast Cable Communications Management, LLC
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vinyldns
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/vinyldns/go-vinyldns/vinyldns"
+)
+
+func dataSourceVinylDNSZone() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceVinylDNSZoneRead,
+
+		Schema: map[string]*schema.Schema{
+			"zone_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			// how recently the zone must have synced for sync_stale to be false
+			"sync_freshness": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "24h",
+				ValidateFunc: validateDuration,
+			},
+			"name": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"email": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"admin_group_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"status": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"shared": &schema.Schema{
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+			"created": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			// vinyldns reports no connection errors, so a latest_sync that has
+			// stopped advancing is the sign of a failing backend connection
+			"latest_sync": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"sync_stale": &schema.Schema{
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+			"zone_connection":     connectionMetadataSchema(),
+			"transfer_connection": connectionMetadataSchema(),
+			// the number of record sets in the zone, for checking against
+			// vinyldns's per-zone record limits
+			"records_count": &schema.Schema{
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func dataSourceVinylDNSZoneRead(d *schema.ResourceData, meta interface{}) error {
+	zoneID := d.Get("zone_id").(string)
+	log.Printf("[INFO] Reading vinyldns zone: %s", zoneID)
+	client := meta.(*Config).Client
+	z, err := client.Zone(zoneID)
+	if err != nil {
+		return zoneAccessError(err, zoneID)
+	}
+
+	rss, err := client.RecordSetsListAll(zoneID, vinyldns.ListFilter{})
+	if err != nil {
+		return err
+	}
+
+	d.SetId(z.ID)
+	d.Set("name", z.Name)
+	d.Set("email", z.Email)
+	d.Set("admin_group_id", z.AdminGroupID)
+	d.Set("status", z.Status)
+	d.Set("shared", z.Shared)
+	d.Set("created", z.Created)
+	d.Set("latest_sync", z.LatestSync)
+
+	// already vetted by validateDuration
+	freshness, _ := time.ParseDuration(d.Get("sync_freshness").(string))
+	stale, err := syncStale(z.LatestSync, freshness, time.Now())
+	if err != nil {
+		return err
+	}
+	d.Set("sync_stale", stale)
+	d.Set("zone_connection", flattenConnectionMetadata(z.Connection))
+	d.Set("transfer_connection", flattenConnectionMetadata(z.TransferConnection))
+	d.Set("records_count", len(rss))
+
+	return nil
+}
+
+// syncStale reports whether a zone last synced at latestSync, or never, has
+// gone longer than freshness without syncing.
+func syncStale(latestSync string, freshness time.Duration, now time.Time) (bool, error) {
+	if latestSync == "" {
+		return true, nil
+	}
+
+	synced, err := time.Parse(time.RFC3339, latestSync)
+	if err != nil {
+		return false, fmt.Errorf("unable to parse zone latest_sync %q: %s", latestSync, err)
+	}
+
+	return now.Sub(synced) > freshness, nil
+}
+
+// connectionMetadataSchema describes a zone connection read back from vinyldns,
+// leaving out its key.
+func connectionMetadataSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeList,
+		Computed: true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"name": &schema.Schema{
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+				"key_name": &schema.Schema{
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+				"primary_server": &schema.Schema{
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+			},
+		},
+	}
+}
+
+// flattenConnectionMetadata returns everything but the key of a zone
+// connection, or nothing for zones using vinyldns's default connection.
+func flattenConnectionMetadata(c *vinyldns.ZoneConnection) []interface{} {
+	if c == nil || c.Name == "" {
+		return []interface{}{}
+	}
+
+	return []interface{}{
+		map[string]interface{}{
+			"name":           c.Name,
+			"key_name":       c.KeyName,
+			"primary_server": c.PrimaryServer,
+		},
+	}
+}
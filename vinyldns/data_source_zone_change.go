@@ -0,0 +1,85 @@
+/*
+Copyright 2018 Comcast Cable Communications Management, LLC
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vinyldns
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/vinyldns/go-vinyldns/vinyldns"
+)
+
+func dataSourceVinylDNSZoneChange() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceVinylDNSZoneChangeRead,
+
+		Schema: map[string]*schema.Schema{
+			"zone_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"change_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"change_type": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"status": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"user_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"created": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func dataSourceVinylDNSZoneChangeRead(d *schema.ResourceData, meta interface{}) error {
+	zoneID := d.Get("zone_id").(string)
+	changeID := d.Get("change_id").(string)
+	log.Printf("[INFO] Reading vinyldns zone change: %s", changeID)
+
+	zc, err := zoneChange(meta, zoneID, changeID)
+	if err != nil {
+		return err
+	}
+
+	d.SetId(fmt.Sprintf("%s:%s", zoneID, changeID))
+	d.Set("change_type", zc.ChangeType)
+	d.Set("status", zc.Status)
+	d.Set("user_id", zc.UserID)
+	d.Set("created", zc.Created)
+
+	return nil
+}
+
+// zoneChange returns the zone change, reporting a missing one plainly rather
+// than as the client's raw 404.
+func zoneChange(meta interface{}, zoneID, changeID string) (*vinyldns.ZoneChange, error) {
+	zc, err := meta.(*Config).Client.ZoneChange(zoneID, changeID)
+	if dErr, ok := err.(*vinyldns.Error); ok && dErr.ResponseCode == http.StatusNotFound {
+		return nil, fmt.Errorf("zone change %s not found in zone %s", changeID, zoneID)
+	}
+
+	return zc, err
+}
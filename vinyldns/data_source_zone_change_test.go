@@ -0,0 +1,47 @@
+/*
+Copyright 2018 Comcast Cable Communications Management, LLC
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vinyldns
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestZoneChange(t *testing.T) {
+	meta, closeServer := testMeta(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"id":"change-id","changeType":"Sync","status":"Synced","userId":"user-id"}`))
+	})
+	defer closeServer()
+
+	zc, err := zoneChange(meta, "zone-id", "change-id")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if zc.ChangeType != "Sync" || zc.Status != "Synced" || zc.UserID != "user-id" {
+		t.Errorf("unexpected zone change: %#v", zc)
+	}
+}
+
+func TestZoneChangeNotFound(t *testing.T) {
+	meta, closeServer := testMeta(func(w http.ResponseWriter, r *http.Request) {
+		http.NotFound(w, r)
+	})
+	defer closeServer()
+
+	_, err := zoneChange(meta, "zone-id", "change-id")
+	if err == nil || !strings.Contains(err.Error(), "zone change change-id not found in zone zone-id") {
+		t.Errorf("expected a not found error; got %v", err)
+	}
+}
@@ -0,0 +1,174 @@
+/*
+Copyright 2018 Comcast Cable Communications Management, LLC
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vinyldns
+
+import (
+	"log"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/vinyldns/go-vinyldns/vinyldns"
+)
+
+func dataSourceVinylDNSZoneInventory() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceVinylDNSZoneInventoryRead,
+
+		Schema: map[string]*schema.Schema{
+			"zone_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"zone_name": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"managed_record_set_ids": &schema.Schema{
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Set:      schema.HashString,
+			},
+			"record_sets": &schema.Schema{
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     inventoryRecordSetResource(),
+			},
+			"unmanaged_record_sets": &schema.Schema{
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     inventoryRecordSetResource(),
+			},
+		},
+	}
+}
+
+func inventoryRecordSetResource() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"id": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"import_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"name": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"type": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"ttl": &schema.Schema{
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"owner_group_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"records_json": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func dataSourceVinylDNSZoneInventoryRead(d *schema.ResourceData, meta interface{}) error {
+	zoneID := d.Get("zone_id").(string)
+	log.Printf("[INFO] Reading vinyldns zone inventory; %s", logFields("zone_id", zoneID))
+	client := meta.(*Config).Client
+
+	z, err := client.Zone(zoneID)
+	if err != nil {
+		return zoneAccessError(err, zoneID)
+	}
+
+	rss, err := client.RecordSetsListAll(zoneID, vinyldns.ListFilter{})
+	if err != nil {
+		return err
+	}
+
+	manageable := []vinyldns.RecordSet{}
+	for _, rs := range rss {
+		if inventoryManageable(rs, z.Name) {
+			manageable = append(manageable, rs)
+		}
+	}
+
+	unmanaged := unmanagedRecordSets(manageable, stringSetToStringSlice(d.Get("managed_record_set_ids").(*schema.Set)))
+	if len(unmanaged) > 0 {
+		log.Printf("[INFO] zone %s has %d record sets not among managed_record_set_ids", z.Name, len(unmanaged))
+	}
+
+	d.SetId(zoneID)
+	d.Set("zone_name", z.Name)
+	d.Set("unmanaged_record_sets", flattenInventoryRecordSets(unmanaged))
+
+	return d.Set("record_sets", flattenInventoryRecordSets(manageable))
+}
+
+// unmanagedRecordSets returns the record sets of rss whose IDs aren't among
+// managed, in the order of rss.
+func unmanagedRecordSets(rss []vinyldns.RecordSet, managed []string) []vinyldns.RecordSet {
+	ids := map[string]bool{}
+	for _, id := range managed {
+		ids[id] = true
+	}
+
+	unmanaged := []vinyldns.RecordSet{}
+	for _, rs := range rss {
+		if !ids[rs.ID] {
+			unmanaged = append(unmanaged, rs)
+		}
+	}
+
+	return unmanaged
+}
+
+// inventoryManageable reports whether rs can be managed by a
+// vinyldns_record_set; vinyldns maintains the zone's SOA and apex NS record
+// sets itself, so importing them would only produce resources that can't apply.
+func inventoryManageable(rs vinyldns.RecordSet, zoneName string) bool {
+	if rs.Type == "SOA" {
+		return false
+	}
+
+	return rs.Type != "NS" || rejectApexNS(rs.Name, zoneName) == nil
+}
+
+func flattenInventoryRecordSets(rss []vinyldns.RecordSet) []map[string]interface{} {
+	flattened := []map[string]interface{}{}
+	for _, rs := range rss {
+		flattened = append(flattened, flattenInventoryRecordSet(rs))
+	}
+
+	return flattened
+}
+
+// flattenInventoryRecordSet describes rs in the form a vinyldns_record_set
+// takes it: its import ID, and its records as accepted by records_json.
+func flattenInventoryRecordSet(rs vinyldns.RecordSet) map[string]interface{} {
+	return map[string]interface{}{
+		"id":             rs.ID,
+		"import_id":      rs.ZoneID + ":" + rs.ID,
+		"name":           rs.Name,
+		"type":           rs.Type,
+		"ttl":            rs.TTL,
+		"owner_group_id": rs.OwnerGroupID,
+		"records_json":   recordsString(rs.Records),
+	}
+}
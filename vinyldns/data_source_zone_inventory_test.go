@@ -0,0 +1,90 @@
+/*
+Copyright 2018 Comcast Cable Communications Management, LLC
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vinyldns
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/vinyldns/go-vinyldns/vinyldns"
+)
+
+func TestInventoryManageable(t *testing.T) {
+	cases := []struct {
+		rs       vinyldns.RecordSet
+		expected bool
+	}{
+		{vinyldns.RecordSet{Name: "www", Type: "A"}, true},
+		{vinyldns.RecordSet{Name: "sub", Type: "NS"}, true},
+		{vinyldns.RecordSet{Name: "system-test.", Type: "NS"}, false},
+		{vinyldns.RecordSet{Name: "@", Type: "NS"}, false},
+		{vinyldns.RecordSet{Name: "system-test.", Type: "SOA"}, false},
+	}
+
+	for _, c := range cases {
+		if got := inventoryManageable(c.rs, "system-test."); got != c.expected {
+			t.Errorf("inventoryManageable(%s %s) = %t; expected %t", c.rs.Type, c.rs.Name, got, c.expected)
+		}
+	}
+}
+
+func TestFlattenInventoryRecordSet(t *testing.T) {
+	rs := vinyldns.RecordSet{
+		ID:           "record-set-id",
+		ZoneID:       "zone-id",
+		Name:         "mail",
+		Type:         "MX",
+		TTL:          300,
+		OwnerGroupID: "group-id",
+		Records:      []vinyldns.Record{{Preference: 10, Exchange: "mx.example.com."}},
+	}
+
+	expected := map[string]interface{}{
+		"id":             "record-set-id",
+		"import_id":      "zone-id:record-set-id",
+		"name":           "mail",
+		"type":           "MX",
+		"ttl":            300,
+		"owner_group_id": "group-id",
+		"records_json":   `[{"preference":10,"exchange":"mx.example.com."}]`,
+	}
+
+	if got := flattenInventoryRecordSet(rs); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %#v; got %#v", expected, got)
+	}
+
+	records, err := parseRecordsJSON(expected["records_json"].(string))
+	if err != nil {
+		t.Fatalf("records_json isn't accepted by a record set: %s", err)
+	}
+	if !recordsMatch(records, rs.Records) {
+		t.Errorf("expected records_json to round trip; got %#v", records)
+	}
+}
+
+func TestUnmanagedRecordSets(t *testing.T) {
+	rss := []vinyldns.RecordSet{{ID: "a"}, {ID: "b"}, {ID: "c"}}
+
+	got := unmanagedRecordSets(rss, []string{"b", "not-in-zone"})
+	if len(got) != 2 || got[0].ID != "a" || got[1].ID != "c" {
+		t.Errorf("expected record sets a and c to be unmanaged; got %#v", got)
+	}
+
+	if got := unmanagedRecordSets(rss, nil); len(got) != 3 {
+		t.Errorf("expected every record set to be unmanaged when none are managed; got %#v", got)
+	}
+
+	if got := unmanagedRecordSets(rss, []string{"a", "b", "c"}); len(got) != 0 {
+		t.Errorf("expected no unmanaged record sets; got %#v", got)
+	}
+}
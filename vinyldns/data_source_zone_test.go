@@ -0,0 +1,127 @@
+/*
+Copyright 2018 Comcast Cable Communications Management, LLC
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vinyldns
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/vinyldns/go-vinyldns/vinyldns"
+)
+
+func TestAccVinylDNSZoneDataSourceBasic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccVinylDNSRecordSetDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccVinylDNSZoneDataSourceConfigBasic,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.vinyldns_zone.test", "name", "system-test."),
+					resource.TestCheckResourceAttr("data.vinyldns_zone.test", "email", "foo@bar.com"),
+					resource.TestCheckResourceAttrSet("data.vinyldns_zone.test", "records_count"),
+					resource.TestCheckResourceAttr("data.vinyldns_zone.test", "zone_connection.#", "0"),
+				),
+			},
+		},
+	})
+}
+
+func TestSyncStale(t *testing.T) {
+	now := time.Date(2019, 4, 3, 12, 0, 0, 0, time.UTC)
+	cases := []struct {
+		latestSync string
+		stale      bool
+	}{
+		{"2019-04-03T11:00:00Z", false},
+		{"2019-04-02T12:00:00Z", false},
+		{"2019-04-01T12:00:00Z", true},
+		{"2019-04-03T11:00:00.123Z", false},
+		{"", true},
+	}
+
+	for _, c := range cases {
+		stale, err := syncStale(c.latestSync, 24*time.Hour, now)
+		if err != nil {
+			t.Errorf("unexpected error for latest_sync %q: %s", c.latestSync, err)
+		}
+		if stale != c.stale {
+			t.Errorf("expected sync_stale %t for latest_sync %q; got %t", c.stale, c.latestSync, stale)
+		}
+	}
+
+	if _, err := syncStale("yesterday", 24*time.Hour, now); err == nil {
+		t.Error("expected an error for an unparseable latest_sync")
+	}
+}
+
+func TestFlattenConnectionMetadata(t *testing.T) {
+	if c := flattenConnectionMetadata(nil); len(c) != 0 {
+		t.Errorf("expected no connection for a nil connection; got %#v", c)
+	}
+
+	if c := flattenConnectionMetadata(&vinyldns.ZoneConnection{}); len(c) != 0 {
+		t.Errorf("expected no connection for an empty connection; got %#v", c)
+	}
+
+	c := flattenConnectionMetadata(&vinyldns.ZoneConnection{
+		Name:          "primary",
+		KeyName:       "vinyldns.",
+		Key:           "secret",
+		PrimaryServer: "10.0.0.1",
+	})
+	expected := []interface{}{
+		map[string]interface{}{
+			"name":           "primary",
+			"key_name":       "vinyldns.",
+			"primary_server": "10.0.0.1",
+		},
+	}
+	if !reflect.DeepEqual(c, expected) {
+		t.Errorf("expected %#v; got %#v", expected, c)
+	}
+}
+
+const testAccVinylDNSZoneDataSourceConfigBasic = `
+resource "vinyldns_group" "test_group" {
+	name = "terraformtestgroup"
+	description = "some description"
+	email = "tftest@tf.com"
+}
+
+resource "vinyldns_zone" "test_zone" {
+	name = "system-test."
+	email = "foo@bar.com"
+	admin_group_id = "${vinyldns_group.test_group.id}"
+	depends_on = [
+		"vinyldns_group.test_group"
+	]
+}
+
+resource "vinyldns_record_set" "test_a_record_set" {
+	name = "terraformtestrecordset"
+	zone_id = "${vinyldns_zone.test_zone.id}"
+	type = "A"
+	ttl = 6000
+	record_addresses = ["127.0.0.1"]
+	depends_on = [
+		"vinyldns_zone.test_zone"
+	]
+}
+
+data "vinyldns_zone" "test" {
+	zone_id = "${vinyldns_record_set.test_a_record_set.zone_id}"
+}`
@@ -0,0 +1,104 @@
+/*
+Copyright 2018 Comcast Cable Communications Management, LLC
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vinyldns
+
+import (
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/hashcode"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/vinyldns/go-vinyldns/vinyldns"
+)
+
+func dataSourceVinylDNSZones() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceVinylDNSZonesRead,
+
+		Schema: map[string]*schema.Schema{
+			"admin_group_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"zones": &schema.Schema{
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"name": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"email": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"admin_group_id": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"status": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"shared": &schema.Schema{
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						"created": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceVinylDNSZonesRead(d *schema.ResourceData, meta interface{}) error {
+	adminGroupID := d.Get("admin_group_id").(string)
+	log.Printf("[INFO] Reading vinyldns zones; admin_group_id: %s", adminGroupID)
+	zones, err := meta.(*Config).Client.ZonesListAll(vinyldns.ListFilter{})
+	if err != nil {
+		return err
+	}
+
+	ids := []string{}
+	matches := []map[string]interface{}{}
+	for _, z := range zones {
+		if adminGroupID != "" && z.AdminGroupID != adminGroupID {
+			continue
+		}
+
+		ids = append(ids, z.ID)
+		matches = append(matches, map[string]interface{}{
+			"id":             z.ID,
+			"name":           z.Name,
+			"email":          z.Email,
+			"admin_group_id": z.AdminGroupID,
+			"status":         z.Status,
+			"shared":         z.Shared,
+			"created":        z.Created,
+		})
+	}
+
+	d.SetId(strconv.Itoa(hashcode.String(adminGroupID + ":" + strings.Join(ids, ","))))
+
+	return d.Set("zones", matches)
+}
@@ -0,0 +1,60 @@
+/*
+Copyright 2018 Comcast Cable Communications Management, LLC
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vinyldns
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+)
+
+func TestAccVinylDNSZonesDataSourceBasic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccVinylDNSZoneDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccVinylDNSZonesDataSourceConfigBasic,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.vinyldns_zones.test", "zones.#", "1"),
+					resource.TestCheckResourceAttr("data.vinyldns_zones.test", "zones.0.name", "system-test."),
+					resource.TestCheckResourceAttr("data.vinyldns_zones.test", "zones.0.email", "foo@bar.com"),
+				),
+			},
+		},
+	})
+}
+
+const testAccVinylDNSZonesDataSourceConfigBasic = `
+resource "vinyldns_group" "test_group" {
+	name = "terraformtestgroup"
+	description = "some description"
+	email = "tftest@tf.com"
+}
+
+resource "vinyldns_zone" "test_zone" {
+	name = "system-test."
+	email = "foo@bar.com"
+	admin_group_id = "${vinyldns_group.test_group.id}"
+	depends_on = [
+		"vinyldns_group.test_group"
+	]
+}
+
+data "vinyldns_zones" "test" {
+	admin_group_id = "${vinyldns_group.test_group.id}"
+	depends_on = [
+		"vinyldns_zone.test_zone"
+	]
+}`
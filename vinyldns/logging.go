@@ -0,0 +1,31 @@
+/*
+Copyright 2018 Comcast Cable Communications Management, LLC
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vinyldns
+
+import (
+	"fmt"
+	"strings"
+)
+
+// logFields formats alternating keys and values as "key=value" pairs, so log
+// lines can be filtered by zone_id, record_set_id or change_id across the many
+// resources of an apply. Terraform filters the lines by their level prefix,
+// such as [DEBUG] or [INFO], according to TF_LOG.
+func logFields(kv ...string) string {
+	pairs := []string{}
+	for i := 0; i+1 < len(kv); i += 2 {
+		pairs = append(pairs, fmt.Sprintf("%s=%s", kv[i], kv[i+1]))
+	}
+
+	return strings.Join(pairs, " ")
+}
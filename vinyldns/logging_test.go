@@ -0,0 +1,34 @@
+/*
+Copyright 2018 Comcast Cable Communications Management, LLC
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vinyldns
+
+import (
+	"testing"
+)
+
+func TestLogFields(t *testing.T) {
+	cases := []struct {
+		kv       []string
+		expected string
+	}{
+		{[]string{"zone_id", "1", "record_set_id", "2", "change_id", "3"}, "zone_id=1 record_set_id=2 change_id=3"},
+		{[]string{"zone_id", "1", "dangling"}, "zone_id=1"},
+		{nil, ""},
+	}
+
+	for _, c := range cases {
+		if f := logFields(c.kv...); f != c.expected {
+			t.Errorf("expected %q; got %q", c.expected, f)
+		}
+	}
+}
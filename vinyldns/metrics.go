@@ -0,0 +1,149 @@
+/*
+Copyright 2018 Comcast Cable Communications Management, LLC
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vinyldns
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// The counters kept by providerMetrics.
+const (
+	metricRecordSetsCreated = "record_sets_created"
+	metricRecordSetsUpdated = "record_sets_updated"
+	metricRecordSetsDeleted = "record_sets_deleted"
+	metricAPIRetries        = "api_retries"
+	metricPollCycles        = "poll_cycles"
+)
+
+// metricNames are every counter providerMetrics keeps, so that the summary
+// includes all of them, however many have been counted.
+var metricNames = []string{
+	metricRecordSetsCreated,
+	metricRecordSetsUpdated,
+	metricRecordSetsDeleted,
+	metricAPIRetries,
+	metricPollCycles,
+}
+
+// providerMetrics counts what the provider asks of vinyldns, for capacity
+// planning, adding the counts to a JSON summary kept in a file. A nil
+// *providerMetrics counts nothing, so callers needn't check for one.
+type providerMetrics struct {
+	path string
+
+	mu sync.Mutex
+}
+
+var (
+	metricsMu sync.Mutex
+	// metricsByPath shares one *providerMetrics between the provider
+	// configurations, such as aliases, that keep their summary in one file, so
+	// that their counts are added up one at a time.
+	metricsByPath = map[string]*providerMetrics{}
+)
+
+// newProviderMetrics returns metrics summarized in the file at path, or nil if
+// path is empty. The file is left as it is until something is counted, so that
+// configuring the provider, as every plan does, doesn't reset it.
+func newProviderMetrics(path string) *providerMetrics {
+	if path == "" {
+		return nil
+	}
+
+	metricsMu.Lock()
+	defer metricsMu.Unlock()
+
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+
+	m, ok := metricsByPath[path]
+	if !ok {
+		m = &providerMetrics{path: path}
+		metricsByPath[path] = m
+	}
+
+	return m
+}
+
+// inc adds one to counter in the summary.
+func (m *providerMetrics) inc(counter string) {
+	if m == nil {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	counters := m.read()
+	counters[counter]++
+	m.write(counters)
+}
+
+// read returns the counts the summary holds, with every counter it's missing,
+// or all of them if there's no summary yet, at 0.
+func (m *providerMetrics) read() map[string]int64 {
+	counters := map[string]int64{}
+
+	b, err := ioutil.ReadFile(m.path)
+	if err == nil {
+		err = json.Unmarshal(b, &counters)
+	}
+	if err != nil && !os.IsNotExist(err) {
+		log.Printf("[WARN] unable to read vinyldns metrics from %s; starting them over: %s", m.path, err)
+		counters = map[string]int64{}
+	}
+
+	for _, name := range metricNames {
+		if _, ok := counters[name]; !ok {
+			counters[name] = 0
+		}
+	}
+
+	return counters
+}
+
+// write replaces the summary with counters. The summary is written to a
+// temporary file of its own first so that it's never read half written.
+// Failing to write it is logged rather than failing the run it describes.
+func (m *providerMetrics) write(counters map[string]int64) {
+	b, err := json.MarshalIndent(counters, "", "  ")
+	if err == nil {
+		var tmp *os.File
+		tmp, err = ioutil.TempFile(filepath.Dir(m.path), "."+filepath.Base(m.path)+".tmp")
+		if err == nil {
+			_, err = tmp.Write(b)
+			if cerr := tmp.Close(); err == nil {
+				err = cerr
+			}
+			if err == nil {
+				err = os.Chmod(tmp.Name(), 0644)
+			}
+			if err == nil {
+				err = os.Rename(tmp.Name(), m.path)
+			}
+			if err != nil {
+				os.Remove(tmp.Name())
+			}
+		}
+	}
+
+	if err != nil {
+		log.Printf("[WARN] unable to write vinyldns metrics to %s: %s", m.path, err)
+	}
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2018 Comcast Cable Communications Management, LLC
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vinyldns
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func readMetrics(t *testing.T, path string) map[string]int64 {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	counters := map[string]int64{}
+	if err := json.Unmarshal(b, &counters); err != nil {
+		t.Fatalf("metrics file isn't a JSON object of counters: %s", err)
+	}
+
+	return counters
+}
+
+func TestProviderMetrics(t *testing.T) {
+	dir, err := ioutil.TempDir("", "vinyldns-metrics")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "metrics.json")
+
+	m := newProviderMetrics(path)
+
+	// configuring the provider leaves the summary alone
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("expected no summary before anything is counted; got %v", err)
+	}
+
+	m.inc(metricRecordSetsCreated)
+	m.inc(metricRecordSetsCreated)
+	m.inc(metricPollCycles)
+
+	counters := readMetrics(t, path)
+	if counters[metricRecordSetsCreated] != 2 || counters[metricPollCycles] != 1 || counters[metricRecordSetsDeleted] != 0 {
+		t.Errorf("unexpected counters: %v", counters)
+	}
+	if len(counters) != len(metricNames) {
+		t.Errorf("expected every counter to be summarized; got %v", counters)
+	}
+
+	// a later run, or an alias sharing the file, adds to the counts
+	delete(metricsByPath, path)
+	later := newProviderMetrics(path)
+	later.inc(metricRecordSetsCreated)
+	if counters := readMetrics(t, path); counters[metricRecordSetsCreated] != 3 || counters[metricPollCycles] != 1 {
+		t.Errorf("expected the counts to be added to; got %v", counters)
+	}
+
+	if newProviderMetrics(path) != later {
+		t.Error("expected provider configurations sharing a file to share metrics")
+	}
+
+	if files, _ := ioutil.ReadDir(dir); len(files) != 1 {
+		t.Errorf("expected only the summary to be left; got %d files", len(files))
+	}
+
+	if newProviderMetrics("") != nil {
+		t.Error("expected no metrics without a metrics file")
+	}
+
+	// nil metrics count nothing, without panicking
+	var none *providerMetrics
+	none.inc(metricAPIRetries)
+}
+
+func TestHTTPClientCountsRetries(t *testing.T) {
+	dir, err := ioutil.TempDir("", "vinyldns-metrics")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "metrics.json")
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Retry-After", "0")
+		switch requests {
+		case 1:
+			w.WriteHeader(http.StatusTooManyRequests)
+		case 2:
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte("VinylDNS is in read-only mode"))
+		}
+	}))
+	defer server.Close()
+
+	if _, err := httpClient("", nil, "", newProviderMetrics(path)).Get(server.URL); err != nil {
+		t.Fatal(err)
+	}
+
+	if counters := readMetrics(t, path); counters[metricAPIRetries] != 2 {
+		t.Errorf("expected 2 retries to be counted; got %v", counters)
+	}
+}
@@ -0,0 +1,223 @@
+/*
+Copyright 2018 Comcast Cable Communications Management, LLC
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vinyldns
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/vinyldns/go-vinyldns/vinyldns"
+)
+
+// propagationPollInterval is how long to wait between DNS lookups of a record
+// set whose change hasn't been observed yet; tests shorten it.
+var propagationPollInterval = 5 * time.Second
+
+// propagationLookupTimeout bounds a single DNS lookup.
+const propagationLookupTimeout = 10 * time.Second
+
+// propagationVerifiableTypes are the record types whose propagation can be
+// verified with the lookups net.Resolver offers.
+var propagationVerifiableTypes = map[string]bool{
+	"A":     true,
+	"AAAA":  true,
+	"CNAME": true,
+	"MX":    true,
+	"NS":    true,
+	"TXT":   true,
+}
+
+// resolverAddress returns addr, a resolver host with an optional port, as the
+// host:port to dial; the port defaults to 53.
+func resolverAddress(addr string) string {
+	if _, _, err := net.SplitHostPort(addr); err == nil {
+		return addr
+	}
+
+	return net.JoinHostPort(strings.Trim(addr, "[]"), "53")
+}
+
+// propagationResolver returns a resolver querying the DNS server at addr, or
+// the system resolver if addr is empty.
+func propagationResolver(addr string) *net.Resolver {
+	if addr == "" {
+		return net.DefaultResolver
+	}
+
+	address := resolverAddress(addr)
+
+	return &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			var dialer net.Dialer
+			return dialer.DialContext(ctx, network, address)
+		},
+	}
+}
+
+// lookupRecords returns the records DNS holds for fqdn of recordType, in the
+// normalized form expectedRecords uses.
+func lookupRecords(ctx context.Context, r *net.Resolver, recordType, fqdn string) ([]string, error) {
+	values := []string{}
+
+	switch recordType {
+	case "A", "AAAA":
+		addrs, err := r.LookupIPAddr(ctx, fqdn)
+		if err != nil {
+			return nil, err
+		}
+		for _, addr := range addrs {
+			if (addr.IP.To4() != nil) == (recordType == "A") {
+				values = append(values, addr.IP.String())
+			}
+		}
+	case "CNAME":
+		cname, err := r.LookupCNAME(ctx, fqdn)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, normalizeName(cname))
+	case "MX":
+		mxs, err := r.LookupMX(ctx, fqdn)
+		if err != nil {
+			return nil, err
+		}
+		for _, mx := range mxs {
+			values = append(values, fmt.Sprintf("%d %s", mx.Pref, normalizeName(mx.Host)))
+		}
+	case "NS":
+		nss, err := r.LookupNS(ctx, fqdn)
+		if err != nil {
+			return nil, err
+		}
+		for _, ns := range nss {
+			values = append(values, normalizeName(ns.Host))
+		}
+	case "TXT":
+		txts, err := r.LookupTXT(ctx, fqdn)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, txts...)
+	default:
+		return nil, fmt.Errorf("propagation of %s record sets can't be verified", recordType)
+	}
+
+	sort.Strings(values)
+
+	return values, nil
+}
+
+// expectedRecords returns records as lookupRecords reports them once they've
+// propagated.
+func expectedRecords(recordType string, records []vinyldns.Record) []string {
+	values := []string{}
+	for _, r := range records {
+		switch recordType {
+		case "A", "AAAA":
+			if ip := net.ParseIP(r.Address); ip != nil {
+				values = append(values, ip.String())
+			} else {
+				values = append(values, r.Address)
+			}
+		case "CNAME":
+			values = append(values, normalizeName(r.CName))
+		case "MX":
+			values = append(values, fmt.Sprintf("%d %s", r.Preference, normalizeName(r.Exchange)))
+		case "NS":
+			values = append(values, normalizeName(r.NSDName))
+		case "TXT":
+			values = append(values, r.Text)
+		}
+	}
+
+	sort.Strings(values)
+
+	return values
+}
+
+func normalizeName(name string) string {
+	return strings.ToLower(strings.TrimSuffix(name, ".")) + "."
+}
+
+// waitForPropagation calls lookup until it returns expected or timeout passes.
+// target describes what's being looked up, and where, for logs and errors.
+func waitForPropagation(target string, expected []string, timeout time.Duration, lookup func() ([]string, error)) error {
+	deadline := time.Now().Add(timeout)
+
+	for {
+		observed, err := lookup()
+		if err == nil && strings.Join(observed, "\n") == strings.Join(expected, "\n") {
+			log.Printf("[DEBUG] %s has propagated: %s", target, strings.Join(observed, ", "))
+			return nil
+		}
+
+		if time.Now().Add(propagationPollInterval).After(deadline) {
+			if err != nil {
+				return fmt.Errorf("%s didn't propagate within %s; last lookup failed: %s", target, timeout, err)
+			}
+
+			return fmt.Errorf("%s didn't propagate within %s; expected [%s], last observed [%s]",
+				target, timeout, strings.Join(expected, ", "), strings.Join(observed, ", "))
+		}
+
+		log.Printf("[DEBUG] %s hasn't propagated yet; observed [%s], err: %v", target, strings.Join(observed, ", "), err)
+		time.Sleep(propagationPollInterval)
+	}
+}
+
+// verifyPropagation waits until every resolver in addrs, or the system
+// resolver if there are none, returns records for the record set.
+func verifyPropagation(rs vinyldns.RecordSet, zoneName string, addrs []string, timeout time.Duration) error {
+	if !propagationVerifiableTypes[rs.Type] {
+		log.Printf("[WARN] propagation of %s record set %s can't be verified; skipping verification", rs.Type, rs.Name)
+		return nil
+	}
+
+	fqdn := zoneName
+	if !isApex(rs.Name, zoneName) {
+		fqdn = strings.TrimSuffix(rs.Name, ".") + "." + zoneName
+	}
+	fqdn = normalizeName(fqdn)
+
+	if len(addrs) == 0 {
+		addrs = []string{""}
+	}
+
+	expected := expectedRecords(rs.Type, rs.Records)
+	deadline := time.Now().Add(timeout)
+	for _, addr := range addrs {
+		resolver := propagationResolver(addr)
+		label := fqdn + " at the system resolver"
+		if addr != "" {
+			label = fqdn + " at resolver " + addr
+		}
+
+		err := waitForPropagation(label, expected, time.Until(deadline), func() ([]string, error) {
+			ctx, cancel := context.WithTimeout(context.Background(), propagationLookupTimeout)
+			defer cancel()
+
+			return lookupRecords(ctx, resolver, rs.Type, fqdn)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
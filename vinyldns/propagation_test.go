@@ -0,0 +1,101 @@
+/*
+Copyright 2018 Comcast Cable Communications Management, LLC
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vinyldns
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vinyldns/go-vinyldns/vinyldns"
+)
+
+func TestResolverAddress(t *testing.T) {
+	cases := map[string]string{
+		"8.8.8.8":          "8.8.8.8:53",
+		"8.8.8.8:5353":     "8.8.8.8:5353",
+		"ns1.example.com":  "ns1.example.com:53",
+		"2001:db8::53":     "[2001:db8::53]:53",
+		"[2001:db8::53]":   "[2001:db8::53]:53",
+		"[2001:db8::53]:5": "[2001:db8::53]:5",
+	}
+
+	for addr, expected := range cases {
+		if got := resolverAddress(addr); got != expected {
+			t.Errorf("resolverAddress(%q) = %q; expected %q", addr, got, expected)
+		}
+	}
+}
+
+func TestExpectedRecords(t *testing.T) {
+	cases := []struct {
+		recordType string
+		records    []vinyldns.Record
+		expected   []string
+	}{
+		{"AAAA", []vinyldns.Record{{Address: "2001:DB8:0:0:0:0:0:1"}}, []string{"2001:db8::1"}},
+		{"A", []vinyldns.Record{{Address: "127.0.0.2"}, {Address: "127.0.0.1"}}, []string{"127.0.0.1", "127.0.0.2"}},
+		{"CNAME", []vinyldns.Record{{CName: "Target.Example.com."}}, []string{"target.example.com."}},
+		{"MX", []vinyldns.Record{{Preference: 10, Exchange: "mx.example.com"}}, []string{"10 mx.example.com."}},
+		{"NS", []vinyldns.Record{{NSDName: "ns1.example.com."}}, []string{"ns1.example.com."}},
+		{"TXT", []vinyldns.Record{{Text: "v=spf1 -all"}}, []string{"v=spf1 -all"}},
+	}
+
+	for _, c := range cases {
+		if got := expectedRecords(c.recordType, c.records); !reflect.DeepEqual(got, c.expected) {
+			t.Errorf("expectedRecords(%s) = %v; expected %v", c.recordType, got, c.expected)
+		}
+	}
+}
+
+func TestWaitForPropagation(t *testing.T) {
+	defer func(d time.Duration) { propagationPollInterval = d }(propagationPollInterval)
+	propagationPollInterval = time.Millisecond
+
+	// stale, then a failed lookup, then propagated
+	lookups := 0
+	lookup := func() ([]string, error) {
+		lookups++
+		switch lookups {
+		case 1:
+			return []string{"127.0.0.1"}, nil
+		case 2:
+			return nil, errors.New("no such host")
+		}
+
+		return []string{"127.0.0.2"}, nil
+	}
+
+	if err := waitForPropagation("www.example.com.", []string{"127.0.0.2"}, time.Minute, lookup); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if lookups != 3 {
+		t.Errorf("expected 3 lookups; got %d", lookups)
+	}
+
+	stale := func() ([]string, error) { return []string{"127.0.0.1"}, nil }
+	err := waitForPropagation("www.example.com.", []string{"127.0.0.2"}, 10*time.Millisecond, stale)
+	if err == nil || !strings.Contains(err.Error(), "last observed [127.0.0.1]") {
+		t.Errorf("expected a timeout naming the stale records; got %v", err)
+	}
+}
+
+func TestVerifyPropagationSkipsUnverifiableTypes(t *testing.T) {
+	rs := vinyldns.RecordSet{Name: "_sip._tcp", Type: "SRV", Records: []vinyldns.Record{{Target: "sip.example.com."}}}
+
+	if err := verifyPropagation(rs, "example.com.", []string{"127.0.0.1:1"}, time.Millisecond); err != nil {
+		t.Errorf("expected verification of a SRV record set to be skipped; got %s", err)
+	}
+}
@@ -13,7 +13,11 @@ limitations under the License.
 package vinyldns
 
 import (
+	"errors"
+	"fmt"
+	"net/url"
 	"os"
+	"time"
 
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/hashicorp/terraform/terraform"
@@ -26,25 +30,117 @@ func Provider() terraform.ResourceProvider {
 		Schema: map[string]*schema.Schema{
 			"access_key": &schema.Schema{
 				Type:        schema.TypeString,
-				Required:    true,
+				Optional:    true,
 				DefaultFunc: envDefaultFunc("VINYLDNS_ACCESS_KEY"),
 			},
 			"secret_key": &schema.Schema{
 				Type:        schema.TypeString,
-				Required:    true,
+				Optional:    true,
 				DefaultFunc: envDefaultFunc("VINYLDNS_SECRET_KEY"),
 			},
-			"host": &schema.Schema{
+			"token": &schema.Schema{
 				Type:        schema.TypeString,
 				Optional:    true,
-				DefaultFunc: envDefaultFunc("VINYLDNS_HOST"),
+				Sensitive:   true,
+				DefaultFunc: envDefaultFunc("VINYLDNS_TOKEN"),
+			},
+			"host": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				DefaultFunc:  envDefaultFunc("VINYLDNS_HOST"),
+				ValidateFunc: validateHost,
+			},
+			"max_concurrency": &schema.Schema{
+				Type:     schema.TypeInt,
+				Optional: true,
+				Default:  4,
+			},
+			"record_poll_delay": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "500ms",
+				ValidateFunc: validateDuration,
+			},
+			"record_poll_min_timeout": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "2s",
+				ValidateFunc: validateDuration,
+			},
+			"metrics_file": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"default_zone_email": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateEmail,
+			},
+			"default_zone_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"cname_auto_trailing_dot": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
 			},
+			"ns_ptr_auto_trailing_dot": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"verify_delete_permission": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"extra_headers": &schema.Schema{
+				Type:     schema.TypeMap,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"max_record_set_entries": &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      1000,
+				ValidateFunc: validateNonNegative,
+			},
+			"workspace_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"team": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"skip_credentials_validation": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+		},
+
+		DataSourcesMap: map[string]*schema.Resource{
+			"vinyldns_batch_change":           dataSourceVinylDNSBatchChange(),
+			"vinyldns_group_members":          dataSourceVinylDNSGroupMembers(),
+			"vinyldns_record_by_fqdn":         dataSourceVinylDNSRecordByFQDN(),
+			"vinyldns_record_set_changes":     dataSourceVinylDNSRecordSetChanges(),
+			"vinyldns_supported_record_types": dataSourceVinylDNSSupportedRecordTypes(),
+			"vinyldns_zone":                   dataSourceVinylDNSZone(),
+			"vinyldns_zone_change":            dataSourceVinylDNSZoneChange(),
+			"vinyldns_zone_inventory":         dataSourceVinylDNSZoneInventory(),
+			"vinyldns_zones":                  dataSourceVinylDNSZones(),
 		},
 
 		ResourcesMap: map[string]*schema.Resource{
-			"vinyldns_group":      resourceVinylDNSGroup(),
-			"vinyldns_zone":       resourceVinylDNSZone(),
-			"vinyldns_record_set": resourceVinylDNSRecordSet(),
+			"vinyldns_batch_change":    resourceVinylDNSBatchChange(),
+			"vinyldns_group":           resourceVinylDNSGroup(),
+			"vinyldns_zone":            resourceVinylDNSZone(),
+			"vinyldns_zone_acl":        resourceVinylDNSZoneACL(),
+			"vinyldns_zone_connection": resourceVinylDNSZoneConnection(),
+			"vinyldns_record_set":      resourceVinylDNSRecordSet(),
+			"vinyldns_record_sets":     resourceVinylDNSRecordSets(),
 		},
 
 		ConfigureFunc: providerConfigure,
@@ -69,11 +165,88 @@ func envDefaultFuncAllowMissing(k string) schema.SchemaDefaultFunc {
 }
 
 func providerConfigure(d *schema.ResourceData) (interface{}, error) {
+	token := d.Get("token").(string)
 	config := vinyldns.ClientConfiguration{
 		AccessKey: d.Get("access_key").(string),
 		SecretKey: d.Get("secret_key").(string),
 		Host:      d.Get("host").(string),
 	}
 
-	return vinyldns.NewClient(config), nil
+	if config.Host == "" {
+		return nil, errors.New("host must be set")
+	}
+
+	if token == "" && (config.AccessKey == "" || config.SecretKey == "") {
+		return nil, errors.New("either token or both access_key and secret_key must be set")
+	}
+
+	// already vetted by validateDuration
+	pollDelay, _ := time.ParseDuration(d.Get("record_poll_delay").(string))
+	pollMinTimeout, _ := time.ParseDuration(d.Get("record_poll_min_timeout").(string))
+
+	extraHeaders := map[string]string{}
+	for k, v := range d.Get("extra_headers").(map[string]interface{}) {
+		extraHeaders[k] = v.(string)
+	}
+
+	metrics := newProviderMetrics(d.Get("metrics_file").(string))
+
+	client := vinyldns.NewClient(config)
+	client.HTTPClient = httpClient(token, extraHeaders, userAgentSuffix(d.Get("workspace_id").(string), d.Get("team").(string)), metrics)
+
+	// surface a bad endpoint or bad credentials now, rather than from the
+	// first resource operation that happens to use them; a single group is
+	// enough to tell, however many the credentials belong to
+	if !d.Get("skip_credentials_validation").(bool) {
+		if _, err := client.GroupsListAll(vinyldns.ListFilter{MaxItems: 1}); err != nil {
+			return nil, fmt.Errorf("unable to authenticate to vinyldns at %s; check host and credentials: %s", config.Host, err)
+		}
+	}
+
+	return &Config{
+		Client:                 client,
+		MaxConcurrency:         d.Get("max_concurrency").(int),
+		RecordPollDelay:        pollDelay,
+		RecordPollMinTimeout:   pollMinTimeout,
+		DefaultZoneEmail:       d.Get("default_zone_email").(string),
+		DefaultZoneID:          d.Get("default_zone_id").(string),
+		CNAMEAutoTrailingDot:   d.Get("cname_auto_trailing_dot").(bool),
+		NSPTRAutoTrailingDot:   d.Get("ns_ptr_auto_trailing_dot").(bool),
+		VerifyDeletePermission: d.Get("verify_delete_permission").(bool),
+		MaxRecordSetEntries:    d.Get("max_record_set_entries").(int),
+		Metrics:                metrics,
+	}, nil
+}
+
+// validateHost ensures the value is an absolute http or https URL.
+func validateHost(v interface{}, k string) (ws []string, es []error) {
+	value := v.(string)
+
+	u, err := url.Parse(value)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		es = append(es, fmt.Errorf("%q must be an http or https URL such as \"https://vinyldns.example.com\"; got %q", k, value))
+	}
+
+	return
+}
+
+// validateDuration ensures the value parses as a Go duration, such as "500ms" or "2s".
+func validateDuration(v interface{}, k string) (ws []string, es []error) {
+	value := v.(string)
+
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 {
+		es = append(es, fmt.Errorf("%q must be a non-negative duration such as \"500ms\" or \"2s\"; got %q", k, value))
+	}
+
+	return
+}
+
+// validateNonNegative ensures the value is zero or greater.
+func validateNonNegative(v interface{}, k string) (ws []string, es []error) {
+	if value := v.(int); value < 0 {
+		es = append(es, fmt.Errorf("%q must not be negative; got %d", k, value))
+	}
+
+	return
 }
@@ -13,10 +13,15 @@ limitations under the License.
 package vinyldns
 
 import (
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
+	"github.com/hashicorp/terraform/config"
+	"github.com/hashicorp/terraform/helper/resource"
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/hashicorp/terraform/terraform"
+	"github.com/vinyldns/go-vinyldns/vinyldns"
 )
 
 var testAccProviders map[string]terraform.ResourceProvider
@@ -29,12 +34,84 @@ func init() {
 	}
 }
 
+func TestMain(m *testing.M) {
+	resource.TestMain(m)
+}
+
+// sweeperClient returns a client configured from the same environment
+// variables consulted by the acceptance tests.
+func sweeperClient() *vinyldns.Client {
+	return vinyldns.NewClientFromEnv()
+}
+
+// testMeta returns provider meta whose client is backed by an httptest server
+// serving handler, along with a func to shut the server down.
+func testMeta(handler http.HandlerFunc) (*Config, func()) {
+	server := httptest.NewServer(handler)
+	client := vinyldns.NewClient(vinyldns.ClientConfiguration{
+		AccessKey: "testAccessKey",
+		SecretKey: "testSecretKey",
+		Host:      server.URL,
+	})
+
+	return &Config{Client: client, MaxConcurrency: 1}, server.Close
+}
+
+// testResourceDiff plans r from the state attributes, or from nothing when
+// they're nil, to the raw configuration.
+func testResourceDiff(t *testing.T, r *schema.Resource, state map[string]string, raw map[string]interface{}, meta interface{}) *terraform.InstanceDiff {
+	c, err := config.NewRawConfig(raw)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	var s *terraform.InstanceState
+	if state != nil {
+		s = &terraform.InstanceState{ID: "resource-id", Attributes: state}
+	}
+
+	diff, err := r.Diff(s, terraform.NewResourceConfig(c), meta)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	return diff
+}
+
 func TestProvider(t *testing.T) {
 	if err := Provider().(*schema.Provider).InternalValidate(); err != nil {
 		t.Fatalf("err: %s", err)
 	}
 }
 
+func TestValidateHost(t *testing.T) {
+	for _, v := range []string{"http://vinyldns.example.com", "https://vinyldns.example.com:9000", "http://localhost:9000/"} {
+		if _, es := validateHost(v, "host"); len(es) != 0 {
+			t.Errorf("expected %q to be valid; got %v", v, es)
+		}
+	}
+
+	for _, v := range []string{"", "vinyldns.example.com", "ftp://vinyldns.example.com", "http://"} {
+		if _, es := validateHost(v, "host"); len(es) == 0 {
+			t.Errorf("expected %q to be invalid", v)
+		}
+	}
+}
+
+func TestValidateDuration(t *testing.T) {
+	for _, v := range []string{"0s", "500ms", "2s", "1m30s"} {
+		if _, es := validateDuration(v, "record_poll_delay"); len(es) != 0 {
+			t.Errorf("expected %q to be valid; got %v", v, es)
+		}
+	}
+
+	for _, v := range []string{"", "500", "soon", "-1s"} {
+		if _, es := validateDuration(v, "record_poll_delay"); len(es) == 0 {
+			t.Errorf("expected %q to be invalid", v)
+		}
+	}
+}
+
 func testAccPreCheck(t *testing.T) {
 
 }
@@ -0,0 +1,84 @@
+/*
+Copyright 2018 Comcast Cable Communications Management, LLC
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vinyldns
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/vinyldns/go-vinyldns/vinyldns"
+)
+
+// SupportedRecordTypes returns the record types the provider can manage, in
+// alphabetical order. SOA record sets are left out as vinyldns doesn't allow
+// them to be created, updated or deleted.
+func SupportedRecordTypes() []string {
+	return []string{"A", "AAAA", "CNAME", "DS", "MX", "NAPTR", "NS", "PTR", "SPF", "SRV", "SSHFP", "TXT"}
+}
+
+// validateRecordType ensures the value is one of SupportedRecordTypes.
+func validateRecordType(v interface{}, k string) (ws []string, es []error) {
+	value := v.(string)
+
+	for _, t := range SupportedRecordTypes() {
+		if value == t {
+			return
+		}
+	}
+
+	es = append(es, fmt.Errorf("%q must be one of %s; got %q", k, strings.Join(SupportedRecordTypes(), ", "), value))
+
+	return
+}
+
+// validateRecords checks the records of a record set of the given type hold
+// the data vinyldns expects of that type, so that every way of configuring
+// records, at plan and at apply, fails the same way on the same mistakes.
+func validateRecords(recordType string, records []vinyldns.Record) error {
+	switch recordType {
+	case "A", "AAAA":
+		if err := requireAddresses(recordType, len(records)); err != nil {
+			return err
+		}
+
+		for _, r := range records {
+			ip := net.ParseIP(r.Address)
+			if ip == nil || (recordType == "A") != (ip.To4() != nil && !strings.Contains(r.Address, ":")) {
+				return fmt.Errorf("%s record set address %q is not a valid %s address", recordType, r.Address, addressFamily(recordType))
+			}
+		}
+	case "CNAME":
+		for _, r := range records {
+			if !strings.HasSuffix(r.CName, ".") {
+				return ErrTrailingDotRequired
+			}
+		}
+	case "NS":
+		for _, r := range records {
+			if !strings.HasSuffix(r.NSDName, ".") {
+				return ErrTrailingDotRequired
+			}
+		}
+	}
+
+	return nil
+}
+
+func addressFamily(recordType string) string {
+	if recordType == "AAAA" {
+		return "IPv6"
+	}
+
+	return "IPv4"
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2018 Comcast Cable Communications Management, LLC
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vinyldns
+
+import (
+	"testing"
+
+	"github.com/vinyldns/go-vinyldns/vinyldns"
+)
+
+func TestValidateRecordType(t *testing.T) {
+	for _, v := range SupportedRecordTypes() {
+		if _, es := validateRecordType(v, "type"); len(es) != 0 {
+			t.Errorf("expected %q to be valid; got %v", v, es)
+		}
+	}
+
+	for _, v := range []string{"", "SOA", "a", "CAA"} {
+		if _, es := validateRecordType(v, "type"); len(es) == 0 {
+			t.Errorf("expected %q to be invalid", v)
+		}
+	}
+}
+
+func TestValidateRecords(t *testing.T) {
+	cases := []struct {
+		recordType string
+		records    []vinyldns.Record
+		expectErr  bool
+	}{
+		{"A", []vinyldns.Record{{Address: "127.0.0.1"}}, false},
+		{"A", []vinyldns.Record{}, true},
+		{"A", []vinyldns.Record{{Address: "127.0.0.1"}, {Address: "not-an-address"}}, true},
+		{"A", []vinyldns.Record{{Address: "2001:db8::1"}}, true},
+		{"A", []vinyldns.Record{{Address: "::ffff:127.0.0.1"}}, true},
+		{"AAAA", []vinyldns.Record{{Address: "2001:db8::1"}}, false},
+		{"AAAA", []vinyldns.Record{{Address: "::ffff:127.0.0.1"}}, false},
+		{"AAAA", []vinyldns.Record{{Address: "127.0.0.1"}}, true},
+		{"AAAA", []vinyldns.Record{}, true},
+		{"CNAME", []vinyldns.Record{{CName: "foo-bar.com."}}, false},
+		{"CNAME", []vinyldns.Record{{CName: "foo-bar.com"}}, true},
+		{"CNAME", []vinyldns.Record{{CName: ""}}, true},
+		{"NS", []vinyldns.Record{{NSDName: "ns1.example.com."}, {NSDName: "ns2.example.com."}}, false},
+		{"NS", []vinyldns.Record{{NSDName: "ns1.example.com."}, {NSDName: "ns2.example.com"}}, true},
+		{"TXT", []vinyldns.Record{{Text: "anything"}}, false},
+	}
+
+	for _, c := range cases {
+		err := validateRecords(c.recordType, c.records)
+		if c.expectErr && err == nil {
+			t.Errorf("expected an error for %s records %#v", c.recordType, c.records)
+		}
+		if !c.expectErr && err != nil {
+			t.Errorf("unexpected error for %s records %#v: %s", c.recordType, c.records, err)
+		}
+	}
+}
@@ -0,0 +1,402 @@
+/*
+Copyright 2018 Comcast Cable Communications Management, LLC
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vinyldns
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/vinyldns/go-vinyldns/vinyldns"
+)
+
+const (
+	// pendingReviewFail fails the apply when a batch change needs manual review.
+	pendingReviewFail = "fail"
+
+	// pendingReviewWait waits for a reviewer to act on the batch change.
+	pendingReviewWait = "wait"
+
+	// pendingReviewSucceed leaves the batch change awaiting review and succeeds.
+	pendingReviewSucceed = "succeed"
+)
+
+func resourceVinylDNSBatchChange() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceVinylDNSBatchChangeCreate,
+		Read:   resourceVinylDNSBatchChangeRead,
+		Update: resourceVinylDNSBatchChangeUpdate,
+		Delete: resourceVinylDNSBatchChangeDelete,
+
+		CustomizeDiff: resourceVinylDNSBatchChangeCustomizeDiff,
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(30 * time.Minute),
+		},
+
+		// a batch change can't be altered once submitted, so any change to what
+		// it submits means submitting a new one
+		Schema: map[string]*schema.Schema{
+			"comments": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+			"owner_group_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+			"change": &schema.Schema{
+				Type:     schema.TypeList,
+				Required: true,
+				ForceNew: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"change_type": &schema.Schema{
+							Type:     schema.TypeString,
+							Required: true,
+							ForceNew: true,
+						},
+						"input_name": &schema.Schema{
+							Type:     schema.TypeString,
+							Required: true,
+							ForceNew: true,
+						},
+						"type": &schema.Schema{
+							Type:         schema.TypeString,
+							Required:     true,
+							ForceNew:     true,
+							ValidateFunc: validateRecordType,
+						},
+						"ttl": &schema.Schema{
+							Type:     schema.TypeInt,
+							Optional: true,
+							ForceNew: true,
+						},
+						"address": &schema.Schema{
+							Type:     schema.TypeString,
+							Optional: true,
+							ForceNew: true,
+						},
+						"cname": &schema.Schema{
+							Type:     schema.TypeString,
+							Optional: true,
+							ForceNew: true,
+						},
+						"ptrdname": &schema.Schema{
+							Type:     schema.TypeString,
+							Optional: true,
+							ForceNew: true,
+						},
+						"text": &schema.Schema{
+							Type:     schema.TypeString,
+							Optional: true,
+							ForceNew: true,
+						},
+						"nsdname": &schema.Schema{
+							Type:     schema.TypeString,
+							Optional: true,
+							ForceNew: true,
+						},
+					},
+				},
+			},
+			// vinyldns limits how many changes one batch change may hold, 1000 by
+			// default; more changes than this are submitted as several batch changes
+			"max_batch_size": &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      1000,
+				ValidateFunc: validatePositive,
+			},
+			"pending_review_behavior": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      pendingReviewFail,
+				ValidateFunc: validatePendingReviewBehavior,
+			},
+			"status": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"created": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			// the resource's ID is the first of these
+			"batch_change_ids": &schema.Schema{
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+func resourceVinylDNSBatchChangeCreate(d *schema.ResourceData, meta interface{}) error {
+	log.Printf("[INFO] Creating vinyldns batch change: %s", d.Get("comments"))
+	behavior := d.Get("pending_review_behavior").(string)
+	deadline := time.Now().Add(d.Timeout(schema.TimeoutCreate))
+	chunks := chunkRecordChanges(recordChanges(d.Get("change").([]interface{})), d.Get("max_batch_size").(int))
+
+	// each batch change is submitted only once the one before it has been
+	// processed, so a failure stops the rest from being submitted
+	ids := []string{}
+	for i, changes := range chunks {
+		log.Printf("[INFO] Submitting vinyldns batch change %d of %d with %d changes", i+1, len(chunks), len(changes))
+		created, err := meta.(*Config).Client.BatchRecordChangeCreate(&vinyldns.BatchRecordChange{
+			Comments:     d.Get("comments").(string),
+			OwnerGroupID: d.Get("owner_group_id").(string),
+			Changes:      changes,
+		})
+		if err != nil {
+			return batchChangesSubmitted(d, ids, err)
+		}
+
+		ids = append(ids, created.ID)
+		d.SetId(ids[0])
+
+		b, err := waitUntilBatchChangeProcessed(meta, created.ID, behavior == pendingReviewWait, time.Until(deadline))
+		if err != nil {
+			return batchChangesSubmitted(d, ids, err)
+		}
+
+		if err := batchChangeResult(b, behavior); err != nil {
+			return batchChangesSubmitted(d, ids, err)
+		}
+	}
+
+	d.Set("batch_change_ids", ids)
+
+	return resourceVinylDNSBatchChangeRead(d, meta)
+}
+
+// batchChangesSubmitted records the batch changes submitted before err stopped
+// the rest, as they can't be taken back.
+func batchChangesSubmitted(d *schema.ResourceData, ids []string, err error) error {
+	d.Set("batch_change_ids", ids)
+	if len(ids) > 1 {
+		return fmt.Errorf("%s; batch changes %s were submitted", err, strings.Join(ids, ", "))
+	}
+
+	return err
+}
+
+func resourceVinylDNSBatchChangeRead(d *schema.ResourceData, meta interface{}) error {
+	log.Printf("[INFO] Reading vinyldns batch change: %s", d.Id())
+	client := meta.(*Config).Client
+
+	// state written before changes were chunked has only the one batch change
+	ids := []string{d.Id()}
+	if v := d.Get("batch_change_ids").([]interface{}); len(v) > 0 {
+		ids = []string{}
+		for _, id := range v {
+			ids = append(ids, id.(string))
+		}
+	}
+
+	statuses := []string{}
+	for _, id := range ids {
+		b, err := client.BatchRecordChange(id)
+		if err != nil {
+			return err
+		}
+
+		if id == d.Id() {
+			d.Set("comments", b.Comments)
+			d.Set("owner_group_id", b.OwnerGroupID)
+			d.Set("created", b.CreatedTimestamp)
+		}
+		statuses = append(statuses, b.Status)
+	}
+
+	d.Set("status", batchChangesStatus(statuses))
+	d.Set("batch_change_ids", ids)
+
+	return nil
+}
+
+// chunkRecordChanges splits changes into consecutive groups of at most size.
+func chunkRecordChanges(changes []vinyldns.RecordChange, size int) [][]vinyldns.RecordChange {
+	chunks := [][]vinyldns.RecordChange{}
+
+	for len(changes) > size {
+		chunks = append(chunks, changes[:size])
+		changes = changes[size:]
+	}
+
+	return append(chunks, changes)
+}
+
+// batchChangesStatus returns the status of several batch changes taken
+// together: the first status that isn't Complete, if any.
+func batchChangesStatus(statuses []string) string {
+	for _, status := range statuses {
+		if status != "Complete" {
+			return status
+		}
+	}
+
+	return "Complete"
+}
+
+// resourceVinylDNSBatchChangeCustomizeDiff plans the replacement of a batch
+// change a reviewer rejected or that was cancelled after it was submitted, as
+// with pending_review_behavior "succeed", so that none of its changes were
+// made and the plan says so.
+func resourceVinylDNSBatchChangeCustomizeDiff(d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() == "" {
+		return nil
+	}
+
+	status := d.Get("status").(string)
+	if status != "Rejected" && status != "Cancelled" {
+		return nil
+	}
+
+	log.Printf("[WARN] vinyldns batch change %s is %s, so none of its changes were made; planning to submit it again", d.Id(), status)
+	if err := d.SetNewComputed("status"); err != nil {
+		return err
+	}
+
+	return d.ForceNew("status")
+}
+
+// only pending_review_behavior and max_batch_size can change in place, and they
+// matter only to Create
+func resourceVinylDNSBatchChangeUpdate(d *schema.ResourceData, meta interface{}) error {
+	return resourceVinylDNSBatchChangeRead(d, meta)
+}
+
+func resourceVinylDNSBatchChangeDelete(d *schema.ResourceData, meta interface{}) error {
+	log.Printf("[WARN] vinyldns batch changes can't be undone; removing batch change %s from state only", d.Id())
+	d.SetId("")
+
+	return nil
+}
+
+func recordChanges(changes []interface{}) []vinyldns.RecordChange {
+	rcs := []vinyldns.RecordChange{}
+
+	for _, v := range changes {
+		c := v.(map[string]interface{})
+		rcs = append(rcs, vinyldns.RecordChange{
+			ChangeType: c["change_type"].(string),
+			InputName:  c["input_name"].(string),
+			Type:       c["type"].(string),
+			TTL:        c["ttl"].(int),
+			Record: vinyldns.RecordData{
+				Address:  recordAddress(c["type"].(string), c["address"].(string)),
+				CName:    c["cname"].(string),
+				PTRDName: c["ptrdname"].(string),
+				Text:     c["text"].(string),
+				NSDName:  c["nsdname"].(string),
+			},
+		})
+	}
+
+	return rcs
+}
+
+// waitUntilBatchChangeProcessed waits for vinyldns to finish processing the batch
+// change. A batch change awaiting manual review is only waited on if waitForReview.
+func waitUntilBatchChangeProcessed(meta interface{}, id string, waitForReview bool, timeout time.Duration) (*vinyldns.BatchRecordChange, error) {
+	pending := []string{"PendingProcessing", "Scheduled"}
+	target := []string{"Complete", "Failed", "PartialFailure", "Rejected", "Cancelled"}
+	if waitForReview {
+		pending = append(pending, "PendingReview")
+	} else {
+		target = append(target, "PendingReview")
+	}
+
+	stateConf := &resource.StateChangeConf{
+		Pending:      pending,
+		Target:       target,
+		Refresh:      batchChangeStateRefreshFunc(meta, id),
+		Timeout:      timeout,
+		Delay:        meta.(*Config).RecordPollDelay,
+		MinTimeout:   meta.(*Config).RecordPollMinTimeout,
+		PollInterval: 500 * time.Millisecond,
+	}
+
+	b, err := stateConf.WaitForState()
+	if err != nil {
+		return nil, err
+	}
+
+	return b.(*vinyldns.BatchRecordChange), nil
+}
+
+func batchChangeStateRefreshFunc(meta interface{}, id string) resource.StateRefreshFunc {
+	return func() (interface{}, string, error) {
+		log.Printf("[INFO] waiting for batch change %s to be processed", id)
+		meta.(*Config).Metrics.inc(metricPollCycles)
+		b, err := meta.(*Config).Client.BatchRecordChange(id)
+		if err != nil {
+			log.Printf("[ERROR] %#v", err)
+			return nil, "", err
+		}
+
+		return b, b.Status, nil
+	}
+}
+
+// batchChangeResult returns an error for a batch change that didn't complete,
+// unless it's awaiting review and behavior allows leaving it pending.
+func batchChangeResult(b *vinyldns.BatchRecordChange, behavior string) error {
+	switch b.Status {
+	case "Complete":
+		return nil
+	case "PendingReview":
+		if behavior == pendingReviewSucceed {
+			log.Printf("[WARN] batch change %s is pending manual review; leaving it pending", b.ID)
+			return nil
+		}
+
+		return fmt.Errorf("batch change %s is pending manual review; a reviewer must approve it in the VinylDNS batch change review queue, "+
+			"or set pending_review_behavior to %q or %q. The resource is tainted, so once the batch change is approved, run `terraform untaint` "+
+			"on it rather than have the next apply submit it again", b.ID, pendingReviewWait, pendingReviewSucceed)
+	}
+
+	messages := []string{}
+	for _, c := range b.Changes {
+		if c.SystemMessage != "" {
+			messages = append(messages, fmt.Sprintf("%s %s: %s", c.InputName, c.Type, c.SystemMessage))
+		}
+	}
+
+	return fmt.Errorf("batch change %s finished with status %s: %s", b.ID, b.Status, strings.Join(messages, "; "))
+}
+
+func validatePendingReviewBehavior(v interface{}, k string) (ws []string, es []error) {
+	value := v.(string)
+
+	if value != pendingReviewFail && value != pendingReviewWait && value != pendingReviewSucceed {
+		es = append(es, fmt.Errorf("%q must be one of %s, %s, or %s; got %q", k, pendingReviewFail, pendingReviewWait, pendingReviewSucceed, value))
+	}
+
+	return
+}
+
+// validatePositive ensures the value is greater than zero.
+func validatePositive(v interface{}, k string) (ws []string, es []error) {
+	if value := v.(int); value < 1 {
+		es = append(es, fmt.Errorf("%q must be greater than zero; got %d", k, value))
+	}
+
+	return
+}
@@ -0,0 +1,178 @@
+/*
+Copyright 2018 Comcast Cable Communications Management, LLC
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vinyldns
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/vinyldns/go-vinyldns/vinyldns"
+)
+
+func TestAccVinylDNSBatchChangeBasic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccVinylDNSZoneDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccVinylDNSBatchChangeConfigBasic,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("vinyldns_batch_change.test", "status", "Complete"),
+					resource.TestCheckResourceAttr("data.vinyldns_batch_change.test", "status", "Complete"),
+					resource.TestCheckResourceAttr("data.vinyldns_batch_change.test", "changes.#", "1"),
+					resource.TestCheckResourceAttr("data.vinyldns_batch_change.test", "changes.0.input_name", "batch-terraformtest.system-test."),
+				),
+			},
+		},
+	})
+}
+
+func TestBatchChangeResult(t *testing.T) {
+	complete := &vinyldns.BatchRecordChange{ID: "batch-id", Status: "Complete"}
+	for _, behavior := range []string{pendingReviewFail, pendingReviewWait, pendingReviewSucceed} {
+		if err := batchChangeResult(complete, behavior); err != nil {
+			t.Errorf("unexpected error for a complete batch change with behavior %s: %s", behavior, err)
+		}
+	}
+
+	pending := &vinyldns.BatchRecordChange{ID: "batch-id", Status: "PendingReview"}
+	if err := batchChangeResult(pending, pendingReviewFail); err == nil || !strings.Contains(err.Error(), "review queue") {
+		t.Errorf("expected an error pointing to the review queue; got %v", err)
+	}
+	if err := batchChangeResult(pending, pendingReviewSucceed); err != nil {
+		t.Errorf("unexpected error for a pending batch change with behavior succeed: %s", err)
+	}
+
+	failed := &vinyldns.BatchRecordChange{
+		ID:     "batch-id",
+		Status: "Failed",
+		Changes: []vinyldns.RecordChange{
+			vinyldns.RecordChange{InputName: "foo.system-test.", Type: "A", SystemMessage: "Zone Discovery Failed"},
+		},
+	}
+	if err := batchChangeResult(failed, pendingReviewSucceed); err == nil || !strings.Contains(err.Error(), "Zone Discovery Failed") {
+		t.Errorf("expected an error including the failed change's message; got %v", err)
+	}
+}
+
+func TestBatchChangePlansReplacementOfRejected(t *testing.T) {
+	state := map[string]string{
+		"comments":                "release",
+		"max_batch_size":          "1000",
+		"pending_review_behavior": pendingReviewSucceed,
+		"change.#":                "1",
+		"change.0.change_type":    "Add",
+		"change.0.input_name":     "www.system-test.",
+		"change.0.type":           "A",
+		"change.0.address":        "10.0.0.1",
+		"batch_change_ids.#":      "1",
+		"batch_change_ids.0":      "resource-id",
+	}
+	raw := map[string]interface{}{
+		"comments":                "release",
+		"pending_review_behavior": pendingReviewSucceed,
+		"change": []interface{}{
+			map[string]interface{}{"change_type": "Add", "input_name": "www.system-test.", "type": "A", "address": "10.0.0.1"},
+		},
+	}
+
+	for _, status := range []string{"Rejected", "Cancelled"} {
+		state["status"] = status
+		diff := testResourceDiff(t, resourceVinylDNSBatchChange(), state, raw, &Config{})
+		if diff == nil || !diff.RequiresNew() {
+			t.Errorf("expected a %s batch change to be replaced; got %#v", status, diff)
+		}
+	}
+
+	state["status"] = "Complete"
+	if diff := testResourceDiff(t, resourceVinylDNSBatchChange(), state, raw, &Config{}); diff != nil && diff.RequiresNew() {
+		t.Errorf("expected a complete batch change to be left as it is; got %#v", diff)
+	}
+}
+
+func TestChunkRecordChanges(t *testing.T) {
+	changes := []vinyldns.RecordChange{}
+	for i := 0; i < 2500; i++ {
+		changes = append(changes, vinyldns.RecordChange{InputName: fmt.Sprintf("host%d.system-test.", i)})
+	}
+
+	chunks := chunkRecordChanges(changes, 1000)
+	if len(chunks) != 3 || len(chunks[0]) != 1000 || len(chunks[1]) != 1000 || len(chunks[2]) != 500 {
+		t.Fatalf("expected chunks of 1000, 1000 and 500 changes; got %d chunks", len(chunks))
+	}
+	if chunks[1][0].InputName != "host1000.system-test." || chunks[2][499].InputName != "host2499.system-test." {
+		t.Errorf("expected changes to keep their order across chunks")
+	}
+
+	if chunks := chunkRecordChanges(changes[:1000], 1000); len(chunks) != 1 {
+		t.Errorf("expected a single chunk for changes within the limit; got %d", len(chunks))
+	}
+}
+
+func TestBatchChangesStatus(t *testing.T) {
+	if status := batchChangesStatus([]string{"Complete", "Complete"}); status != "Complete" {
+		t.Errorf("expected Complete; got %s", status)
+	}
+
+	if status := batchChangesStatus([]string{"Complete", "PendingReview", "Failed"}); status != "PendingReview" {
+		t.Errorf("expected the first status that isn't Complete; got %s", status)
+	}
+}
+
+func TestValidatePendingReviewBehavior(t *testing.T) {
+	for _, v := range []string{"fail", "wait", "succeed"} {
+		if _, es := validatePendingReviewBehavior(v, "pending_review_behavior"); len(es) != 0 {
+			t.Errorf("expected %q to be valid; got %v", v, es)
+		}
+	}
+
+	for _, v := range []string{"", "Fail", "ignore"} {
+		if _, es := validatePendingReviewBehavior(v, "pending_review_behavior"); len(es) == 0 {
+			t.Errorf("expected %q to be invalid", v)
+		}
+	}
+}
+
+const testAccVinylDNSBatchChangeConfigBasic = `
+resource "vinyldns_group" "test_group" {
+	name = "terraformtestgroup"
+	description = "some description"
+	email = "tftest@tf.com"
+}
+
+resource "vinyldns_zone" "test_zone" {
+	name = "system-test."
+	email = "foo@bar.com"
+	admin_group_id = "${vinyldns_group.test_group.id}"
+	depends_on = [
+		"vinyldns_group.test_group"
+	]
+}
+
+resource "vinyldns_batch_change" "test" {
+	comments = "terraform acceptance test"
+	change {
+		change_type = "Add"
+		input_name = "batch-terraformtest.${vinyldns_zone.test_zone.name}"
+		type = "A"
+		ttl = 6000
+		address = "127.0.0.1"
+	}
+}
+
+data "vinyldns_batch_change" "test" {
+	batch_change_id = "${vinyldns_batch_change.test.id}"
+}`
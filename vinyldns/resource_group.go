@@ -15,6 +15,7 @@ package vinyldns
 import (
 	"fmt"
 	"log"
+	"net/http"
 
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/vinyldns/go-vinyldns/vinyldns"
@@ -26,6 +27,9 @@ func resourceVinylDNSGroup() *schema.Resource {
 		Read:   resourceVinylDNSGroupRead,
 		Update: resourceVinylDNSGroupUpdate,
 		Delete: resourceVinylDNSGroupDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceVinylDNSGroupImport,
+		},
 
 		Schema: map[string]*schema.Schema{
 			"name": &schema.Schema{
@@ -33,8 +37,9 @@ func resourceVinylDNSGroup() *schema.Resource {
 				Required: true,
 			},
 			"email": &schema.Schema{
-				Type:     schema.TypeString,
-				Required: true,
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validateEmail,
 			},
 			"description": &schema.Schema{
 				Type:     schema.TypeString,
@@ -49,7 +54,7 @@ func resourceVinylDNSGroup() *schema.Resource {
 func resourceVinylDNSGroupCreate(d *schema.ResourceData, meta interface{}) error {
 	name := d.Get("name").(string)
 	log.Printf("[INFO] Creating Group: %s", name)
-	created, err := meta.(*vinyldns.Client).GroupCreate(&vinyldns.Group{
+	created, err := meta.(*Config).Client.GroupCreate(&vinyldns.Group{
 		Name:        d.Get("name").(string),
 		Email:       d.Get("email").(string),
 		Description: d.Get("description").(string),
@@ -57,7 +62,7 @@ func resourceVinylDNSGroupCreate(d *schema.ResourceData, meta interface{}) error
 		Admins:      users("admin", d),
 	})
 	if err != nil {
-		return err
+		return groupConflictError(meta, err, name)
 	}
 
 	d.SetId(created.ID)
@@ -67,19 +72,38 @@ func resourceVinylDNSGroupCreate(d *schema.ResourceData, meta interface{}) error
 
 func resourceVinylDNSGroupRead(d *schema.ResourceData, meta interface{}) error {
 	log.Printf("[INFO] Reading vinyldns group: %s", d.Id())
-	g, err := meta.(*vinyldns.Client).Group(d.Id())
+	g, err := meta.(*Config).Client.Group(d.Id())
 	if err != nil {
 		return err
 	}
 
 	d.Set("name", g.Name)
+	d.Set("email", g.Email)
+	d.Set("description", g.Description)
 
-	return nil
+	// an imported group has no membership in state yet, so without this the
+	// first apply after import would diff every member and admin; as the
+	// attributes are computed, a group configured without them doesn't diff
+	if err := d.Set("member", flattenUsers(d.Get("member").([]interface{}), g.Members)); err != nil {
+		return err
+	}
+
+	return d.Set("admin", flattenUsers(d.Get("admin").([]interface{}), g.Admins))
+}
+
+// resourceVinylDNSGroupImport fetches the group being imported, membership and
+// all, so that its state matches its configuration.
+func resourceVinylDNSGroupImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	if err := resourceVinylDNSGroupRead(d, meta); err != nil {
+		return nil, err
+	}
+
+	return []*schema.ResourceData{d}, nil
 }
 
 func resourceVinylDNSGroupUpdate(d *schema.ResourceData, meta interface{}) error {
 	log.Printf("[INFO] Updating vinyldns group: %s", d.Id())
-	_, err := meta.(*vinyldns.Client).GroupUpdate(d.Id(), &vinyldns.Group{
+	_, err := meta.(*Config).Client.GroupUpdate(d.Id(), &vinyldns.Group{
 		ID:          d.Id(),
 		Name:        d.Get("name").(string),
 		Email:       d.Get("email").(string),
@@ -88,7 +112,7 @@ func resourceVinylDNSGroupUpdate(d *schema.ResourceData, meta interface{}) error
 		Admins:      users("admin", d),
 	})
 	if err != nil {
-		return err
+		return groupConflictError(meta, err, d.Get("name").(string))
 	}
 
 	return resourceVinylDNSGroupRead(d, meta)
@@ -97,7 +121,7 @@ func resourceVinylDNSGroupUpdate(d *schema.ResourceData, meta interface{}) error
 func resourceVinylDNSGroupDelete(d *schema.ResourceData, meta interface{}) error {
 	log.Printf("[INFO] Deleting vinyldns group: %s", d.Id())
 
-	_, err := meta.(*vinyldns.Client).GroupDelete(d.Id())
+	_, err := meta.(*Config).Client.GroupDelete(d.Id())
 	if err != nil {
 		return err
 	}
@@ -107,10 +131,32 @@ func resourceVinylDNSGroupDelete(d *schema.ResourceData, meta interface{}) error
 	return nil
 }
 
+// groupConflictError explains the 409 vinyldns returns for a group named like
+// one that already exists, as group names are unique. Other errors are
+// returned as they are.
+func groupConflictError(meta interface{}, err error, name string) error {
+	dErr, ok := err.(*vinyldns.Error)
+	if !ok || dErr.ResponseCode != http.StatusConflict {
+		return err
+	}
+
+	id, idErr := groupIDByName(meta, name)
+	if idErr != nil {
+		return fmt.Errorf("a vinyldns group named %s already exists, and group names are unique; "+
+			"to manage it here, import it with terraform import: %s", name, err)
+	}
+
+	return fmt.Errorf("a vinyldns group named %s already exists as group %s, and group names are unique; "+
+		"to manage it here, import it with terraform import vinyldns_group.<name> %s: %s", name, id, id, err)
+}
+
+// userSchema is computed, as vinyldns adds a group's creator as an admin: a
+// group configured without members or admins takes them from vinyldns.
 func userSchema() *schema.Schema {
 	return &schema.Schema{
 		Type:     schema.TypeList,
 		Optional: true,
+		Computed: true,
 		Elem: &schema.Resource{
 			Schema: map[string]*schema.Schema{
 				"user_name": &schema.Schema{
@@ -161,3 +207,82 @@ func users(userType string, d *schema.ResourceData) []vinyldns.User {
 
 	return users
 }
+
+// flattenUsers returns the group's users in state form. Users already in state
+// keep their order and, as vinyldns may return little more than a user's id,
+// any details vinyldns leaves empty; other users follow in vinyldns's order.
+func flattenUsers(current []interface{}, users []vinyldns.User) []interface{} {
+	byID := map[string]vinyldns.User{}
+	for _, u := range users {
+		byID[u.ID] = u
+	}
+
+	flattened := []interface{}{}
+	seen := map[string]bool{}
+	for _, c := range current {
+		prev := c.(map[string]interface{})
+		id := prev["id"].(string)
+		u, ok := byID[id]
+		if !ok || seen[id] {
+			continue
+		}
+
+		seen[id] = true
+		flattened = append(flattened, map[string]interface{}{
+			"id":         id,
+			"user_name":  firstNonEmpty(u.UserName, prev["user_name"]),
+			"first_name": firstNonEmpty(u.FirstName, prev["first_name"]),
+			"last_name":  firstNonEmpty(u.LastName, prev["last_name"]),
+			"email":      firstNonEmpty(u.Email, prev["email"]),
+			"created":    firstNonEmpty(u.Created, prev["created"]),
+		})
+	}
+
+	for _, u := range users {
+		if seen[u.ID] {
+			continue
+		}
+
+		seen[u.ID] = true
+		flattened = append(flattened, map[string]interface{}{
+			"id":         u.ID,
+			"user_name":  u.UserName,
+			"first_name": u.FirstName,
+			"last_name":  u.LastName,
+			"email":      u.Email,
+			"created":    u.Created,
+		})
+	}
+
+	return flattened
+}
+
+func firstNonEmpty(v string, fallback interface{}) string {
+	if v != "" {
+		return v
+	}
+
+	if s, ok := fallback.(string); ok {
+		return s
+	}
+
+	return ""
+}
+
+// groupIDByName returns the ID of the group with exactly the given name.
+func groupIDByName(meta interface{}, name string) (string, error) {
+	groups, err := meta.(*Config).Client.GroupsListAll(vinyldns.ListFilter{
+		NameFilter: name,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	for _, g := range groups {
+		if g.Name == name {
+			return g.ID, nil
+		}
+	}
+
+	return "", fmt.Errorf("no group named %s was found among the groups you belong to", name)
+}
@@ -13,7 +13,11 @@ limitations under the License.
 package vinyldns
 
 import (
+	"errors"
 	"fmt"
+	"log"
+	"net/http"
+	"strings"
 	"testing"
 
 	"github.com/vinyldns/go-vinyldns/vinyldns"
@@ -22,6 +26,35 @@ import (
 	"github.com/hashicorp/terraform/terraform"
 )
 
+func init() {
+	resource.AddTestSweepers("vinyldns_group", &resource.Sweeper{
+		Name:         "vinyldns_group",
+		Dependencies: []string{"vinyldns_zone"},
+		F:            testSweepVinylDNSGroups,
+	})
+}
+
+func testSweepVinylDNSGroups(region string) error {
+	client := sweeperClient()
+	groups, err := client.GroupsListAll(vinyldns.ListFilter{})
+	if err != nil {
+		return fmt.Errorf("Error listing groups to sweep: %s", err)
+	}
+
+	for _, g := range groups {
+		if !strings.HasPrefix(g.Name, "terraformtest") {
+			continue
+		}
+
+		log.Printf("[INFO] sweeping group %s", g.Name)
+		if _, err := client.GroupDelete(g.ID); err != nil {
+			return fmt.Errorf("Error sweeping group %s: %s", g.ID, err)
+		}
+	}
+
+	return nil
+}
+
 func TestAccVinylDNSGroupBasic(t *testing.T) {
 	resource.Test(t, resource.TestCase{
 		PreCheck:     func() { testAccPreCheck(t) },
@@ -35,12 +68,130 @@ func TestAccVinylDNSGroupBasic(t *testing.T) {
 					resource.TestCheckResourceAttr("vinyldns_group.test_group", "name", "terraformtestgroup"),
 				),
 			},
+			// vinyldns adds the group's creator as an admin, which a group
+			// configured without admins must not plan to remove
+			resource.TestStep{
+				Config:   testAccVinylDNSGroupConfigBasic,
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func TestAccVinylDNSGroupImport(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccVinylDNSGroupDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccVinylDNSGroupConfigMembers,
+			},
+			resource.TestStep{
+				ResourceName:      "vinyldns_group.test_group",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			// a plan of the imported group's configuration must be empty
+			resource.TestStep{
+				Config:   testAccVinylDNSGroupConfigMembers,
+				PlanOnly: true,
+			},
 		},
 	})
 }
 
+func TestGroupIDByName(t *testing.T) {
+	meta, closeServer := testMeta(func(w http.ResponseWriter, r *http.Request) {
+		// vinyldns filters groups by prefix, so near matches come back too
+		w.Write([]byte(`{"groups":[{"id":"web-admins-id","name":"web-admins"},{"id":"web-id","name":"web"}]}`))
+	})
+	defer closeServer()
+
+	id, err := groupIDByName(meta, "web")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if id != "web-id" {
+		t.Errorf("expected the exactly named group; got %s", id)
+	}
+
+	if _, err := groupIDByName(meta, "ops"); err == nil {
+		t.Error("expected an error for a group that doesn't exist")
+	}
+}
+
+func TestGroupConflictError(t *testing.T) {
+	meta, closeServer := testMeta(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "POST" {
+			w.WriteHeader(http.StatusConflict)
+			w.Write([]byte(`"Group with name web already exists"`))
+			return
+		}
+
+		w.Write([]byte(`{"groups":[{"id":"web-id","name":"web"}]}`))
+	})
+	defer closeServer()
+
+	_, err := meta.Client.GroupCreate(&vinyldns.Group{Name: "web", Email: "web@example.com"})
+	err = groupConflictError(meta, err, "web")
+	if err == nil || !strings.Contains(err.Error(), "terraform import vinyldns_group.<name> web-id") {
+		t.Errorf("expected an error suggesting the existing group be imported; got %v", err)
+	}
+
+	other := errors.New("boom")
+	if err := groupConflictError(meta, other, "web"); err != other {
+		t.Errorf("expected other errors to be returned unchanged; got %v", err)
+	}
+}
+
+func TestGroupEmailValidation(t *testing.T) {
+	validate := resourceVinylDNSGroup().Schema["email"].ValidateFunc
+
+	if _, errs := validate("web@example.com", "email"); len(errs) != 0 {
+		t.Errorf("unexpected errors for a valid email: %v", errs)
+	}
+
+	for _, v := range []string{"", "web", "web@", "Web <web@example.com>"} {
+		if _, errs := validate(v, "email"); len(errs) == 0 {
+			t.Errorf("expected an error for invalid email %q", v)
+		}
+	}
+}
+
+func TestFlattenUsers(t *testing.T) {
+	current := []interface{}{
+		map[string]interface{}{"id": "b", "user_name": "bee", "first_name": "", "last_name": "", "email": "", "created": ""},
+		map[string]interface{}{"id": "gone", "user_name": "", "first_name": "", "last_name": "", "email": "", "created": ""},
+		map[string]interface{}{"id": "a", "user_name": "", "first_name": "", "last_name": "", "email": "", "created": ""},
+	}
+	users := []vinyldns.User{
+		vinyldns.User{ID: "a", Email: "a@example.com"},
+		vinyldns.User{ID: "c"},
+		vinyldns.User{ID: "b"},
+	}
+
+	flattened := flattenUsers(current, users)
+
+	ids := []string{}
+	for _, u := range flattened {
+		ids = append(ids, u.(map[string]interface{})["id"].(string))
+	}
+	if strings.Join(ids, ",") != "b,a,c" {
+		t.Errorf("expected users in state order followed by new users; got %s", strings.Join(ids, ","))
+	}
+
+	if n := flattened[0].(map[string]interface{})["user_name"]; n != "bee" {
+		t.Errorf("expected a user name vinyldns omits to be kept from state; got %q", n)
+	}
+
+	if e := flattened[1].(map[string]interface{})["email"]; e != "a@example.com" {
+		t.Errorf("expected the email vinyldns returned; got %q", e)
+	}
+}
+
 func testAccVinylDNSGroupDestroy(s *terraform.State) error {
-	client := testAccProvider.Meta().(*vinyldns.Client)
+	client := testAccProvider.Meta().(*Config).Client
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "vinyldns_group" {
@@ -68,7 +219,7 @@ func testAccCheckVinylDNSGroupExists(n string) resource.TestCheckFunc {
 			return fmt.Errorf("No Group ID is set")
 		}
 
-		client := testAccProvider.Meta().(*vinyldns.Client)
+		client := testAccProvider.Meta().(*Config).Client
 
 		g, err := client.Group(rs.Primary.ID)
 		if err != nil {
@@ -92,3 +243,16 @@ resource "vinyldns_group" "test_group" {
 	description = "some description"
 	email = "tftest@tf.com"
 }`
+
+const testAccVinylDNSGroupConfigMembers = `
+resource "vinyldns_group" "test_group" {
+	name = "terraformtestgroup"
+	description = "some description"
+	email = "tftest@tf.com"
+	member {
+		id = "ok"
+	}
+	admin {
+		id = "ok"
+	}
+}`
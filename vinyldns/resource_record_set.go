@@ -1,9 +1,16 @@
 package vinyldns
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"log"
+	"net"
 	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -13,6 +20,20 @@ import (
 	"github.com/vinyldns/go-vinyldns/vinyldns"
 )
 
+var (
+	// ErrUnsupportedType is returned for record types, such as SOA, that
+	// vinyldns doesn't allow to be created, updated or deleted.
+	ErrUnsupportedType = errors.New("record type is not currently supported by vinyldns")
+
+	// ErrTrailingDotRequired is returned when a record value that must be a
+	// fully qualified domain name, such as a CNAME target, lacks its trailing '.'.
+	ErrTrailingDotRequired = errors.New("record value must end in trailing '.'")
+
+	// ErrRecordSetChangeFailed is returned when vinyldns fails a record set
+	// change rather than apply it.
+	ErrRecordSetChangeFailed = errors.New("record set status Failed")
+)
+
 func resourceVinylDNSRecordSet() *schema.Resource {
 	return &schema.Resource{
 		Create: resourceVinylDNSRecordSetCreate,
@@ -20,254 +41,1924 @@ func resourceVinylDNSRecordSet() *schema.Resource {
 		Update: resourceVinylDNSRecordSetUpdate,
 		Delete: resourceVinylDNSRecordSetDelete,
 
+		Importer: &schema.ResourceImporter{
+			State: resourceVinylDNSRecordSetImport,
+		},
+
+		CustomizeDiff: resourceVinylDNSRecordSetCustomizeDiff,
+
+		SchemaVersion: 1,
+		MigrateState:  resourceVinylDNSRecordSetMigrateState,
+
 		Schema: map[string]*schema.Schema{
+			// vinyldns doesn't support renaming a record set, so a new name means a new record set
 			"name": &schema.Schema{
-				Type:     schema.TypeString,
-				Required: true,
+				Type:          schema.TypeString,
+				Optional:      true,
+				Computed:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"host"},
+			},
+			"host": &schema.Schema{
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"name"},
+				ValidateFunc:  validateHostOctets,
+				// imported record sets have a name but no host
+				DiffSuppressFunc: suppressImportedHostDiff,
 			},
+			// defaults to the provider's default_zone_id; vinyldns can't move a
+			// record set between zones, so a new zone means a new record set
 			"zone_id": &schema.Schema{
 				Type:     schema.TypeString,
-				Required: true,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
 			},
 			"type": &schema.Schema{
-				Type:     schema.TypeString,
-				Required: true,
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validateRecordType,
 			},
+			// vinyldns may adjust or default the ttl, so whatever it settles on is read back
 			"ttl": &schema.Schema{
-				Type:     schema.TypeInt,
-				Optional: true,
+				Type:          schema.TypeInt,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: []string{"ttl_duration"},
+			},
+			// a more readable spelling of ttl, converted to it during plan
+			"ttl_duration": &schema.Schema{
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"ttl"},
+				ValidateFunc:  validateTTLDuration,
+				// imported record sets have a ttl but no ttl_duration
+				DiffSuppressFunc: suppressImportedTTLDurationDiff,
+			},
+			"zone_name": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			// whether zone_id was taken from the provider's default_zone_id, so
+			// that a new default moves the record set
+			"zone_id_from_default": &schema.Schema{
+				Type:     schema.TypeBool,
+				Computed: true,
 			},
 			"account": &schema.Schema{
 				Type:     schema.TypeString,
 				Computed: true,
 			},
+			// vinyldns has no record set ETag, so the updated timestamp stands in
+			// as the version guarding against stale writes
+			"updated": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			// every record as vinyldns returns it, whatever the type
+			"records": &schema.Schema{
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     recordResource(),
+			},
 			"record_addresses": &schema.Schema{
 				Type:     schema.TypeSet,
 				Optional: true,
-				Elem:     &schema.Schema{Type: schema.TypeString},
+				Elem: &schema.Schema{
+					Type:      schema.TypeString,
+					StateFunc: normalizeAddress,
+				},
 				Set: func(v interface{}) int {
-					return hashcode.String(v.(string))
+					return hashcode.String(normalizeAddress(v))
 				},
 			},
 			"record_nsdnames": &schema.Schema{
 				Type:     schema.TypeSet,
 				Optional: true,
 				Elem:     &schema.Schema{Type: schema.TypeString},
+				Set:      hashName,
+			},
+			"record_ptrdnames": &schema.Schema{
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Set:      hashName,
+			},
+			"record_mx": &schema.Schema{
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"preference": &schema.Schema{
+							Type:     schema.TypeInt,
+							Required: true,
+						},
+						"exchange": &schema.Schema{
+							Type:     schema.TypeString,
+							Required: true,
+						},
+					},
+				},
+				Set: mxRecordHash,
+			},
+			"record_cname": &schema.Schema{
+				Type:             schema.TypeString,
+				Optional:         true,
+				DiffSuppressFunc: suppressTrailingDotDiff,
+			},
+			"record_text": &schema.Schema{
+				Type:          schema.TypeString,
+				Optional:      true,
+				Deprecated:    "use record_texts",
+				ConflictsWith: []string{"record_texts"},
+			},
+			// computed so state migrated from record_text plans no change for
+			// configuration still using record_text
+			"record_texts": &schema.Schema{
+				Type:          schema.TypeSet,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: []string{"record_text"},
+				Elem:          &schema.Schema{Type: schema.TypeString},
 				Set: func(v interface{}) int {
 					return hashcode.String(v.(string))
 				},
 			},
-			"record_cname": &schema.Schema{
+			// an escape hatch for record data the typed fields above don't model
+			"records_json": &schema.Schema{
+				Type:             schema.TypeString,
+				Optional:         true,
+				ConflictsWith:    []string{"record_addresses", "record_nsdnames", "record_ptrdnames", "record_mx", "record_cname", "record_text", "record_texts"},
+				ValidateFunc:     validateRecordsJSON,
+				DiffSuppressFunc: suppressEquivalentRecordsJSON,
+			},
+			// required by vinyldns for record sets created in shared zones
+			"owner_group_id": &schema.Schema{
 				Type:     schema.TypeString,
 				Optional: true,
 			},
-			"record_text": &schema.Schema{
-				Type:     schema.TypeString,
+			// vinyldns has no creation group of its own; in shared zones the
+			// group is recorded as the record set's owner when it's created
+			"created_by_group": &schema.Schema{
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"owner_group_id"},
+			},
+			// vinyldns record sets carry no labels, so tags live only in terraform state
+			"tags": &schema.Schema{
+				Type:     schema.TypeMap,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"reconcile_on_timeout": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"retry_failed_changes": &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      0,
+				ValidateFunc: validateNonNegative,
+			},
+			"verify_propagation": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"propagation_resolvers": &schema.Schema{
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"propagation_timeout": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "5m",
+				ValidateFunc: validateDuration,
+			},
+			"preserve_unmanaged_fields": &schema.Schema{
+				Type:     schema.TypeBool,
 				Optional: true,
+				Default:  false,
 			},
 		},
 	}
 }
 
 func resourceVinylDNSRecordSetCreate(d *schema.ResourceData, meta interface{}) error {
-	name := d.Get("name").(string)
-	log.Printf("[INFO] Creating vinyldns record set: %s", name)
-	records, err := records(d)
+	if _, err := recordSetZoneID(d, meta); err != nil {
+		return err
+	}
+
+	name, err := recordSetName(d, meta)
 	if err != nil {
 		return err
 	}
-	created, err := meta.(*vinyldns.Client).RecordSetCreate(&vinyldns.RecordSet{
-		Name:    d.Get("name").(string),
-		ZoneID:  d.Get("zone_id").(string),
-		Type:    d.Get("type").(string),
-		TTL:     d.Get("ttl").(int),
-		Records: records,
-	})
+
+	log.Printf("[INFO] Creating vinyldns record set: %s; %s", name, logFields("zone_id", d.Get("zone_id").(string)))
+	records, err := records(d, meta)
+	if err != nil {
+		return err
+	}
+
+	rs := &vinyldns.RecordSet{
+		Name:         name,
+		ZoneID:       d.Get("zone_id").(string),
+		Type:         d.Get("type").(string),
+		TTL:          d.Get("ttl").(int),
+		OwnerGroupID: ownerGroupID(d),
+		Records:      records,
+	}
+
+	existing, err := adoptableRecordSet(meta, rs)
 	if err != nil {
 		return err
 	}
 
-	d.SetId(created.RecordSet.ID)
+	if existing != nil {
+		log.Printf("[INFO] Adopting existing vinyldns record set; %s", logFields("zone_id", rs.ZoneID, "record_set_id", existing.ID))
+		d.SetId(existing.ID)
+
+		return resourceVinylDNSRecordSetRead(d, meta)
+	}
+
+	err = submitRecordSetChange(d.Get("retry_failed_changes").(int), func() (string, error) {
+		created, err := meta.(*Config).Client.RecordSetCreate(rs)
+		if err != nil {
+			return "", recordSetAccessError(err, rs.ZoneID)
+		}
 
-	err = waitUntilRecordSetDeployed(d, meta, created.ChangeID)
+		d.SetId(created.RecordSet.ID)
+		log.Printf("[DEBUG] Submitted vinyldns record set change; %s", logFields("zone_id", rs.ZoneID, "record_set_id", d.Id(), "change_id", created.ChangeID))
+
+		return created.ChangeID, nil
+	}, func(changeID string) error {
+		return waitUntilRecordSetDeployed(d, meta, changeID)
+	})
 	if err != nil {
 		return err
 	}
+	meta.(*Config).Metrics.inc(metricRecordSetsCreated)
+
+	if err := verifyRecordSetPropagation(d, meta, rs); err != nil {
+		return err
+	}
 
 	return resourceVinylDNSRecordSetRead(d, meta)
 }
 
 func resourceVinylDNSRecordSetRead(d *schema.ResourceData, meta interface{}) error {
-	log.Printf("[INFO] Reading vinyldns record set: %s", d.Id())
-	rs, err := meta.(*vinyldns.Client).RecordSet(d.Get("zone_id").(string), d.Id())
+	log.Printf("[INFO] Reading vinyldns record set; %s", logFields("zone_id", d.Get("zone_id").(string), "record_set_id", d.Id()))
+	client := meta.(*Config).Client
+	rs, err := client.RecordSet(d.Get("zone_id").(string), d.Id())
 	if err != nil {
-		return err
+		missing, zErr := zoneMissing(meta, d.Get("zone_id").(string), err)
+		if zErr != nil {
+			return zErr
+		}
+
+		if missing {
+			log.Printf("[WARN] zone %s of record set %s no longer exists; removing the record set from state", d.Get("zone_id"), d.Id())
+			d.SetId("")
+			return nil
+		}
+
+		return zoneAccessError(err, d.Get("zone_id").(string))
 	}
 
 	d.Set("name", rs.Name)
+	d.Set("type", rs.Type)
+	d.Set("ttl", rs.TTL)
+	// an owner set through created_by_group isn't one owner_group_id manages
+	if cbg := d.Get("created_by_group").(string); cbg != "" && cbg == rs.OwnerGroupID {
+		d.Set("owner_group_id", "")
+	} else {
+		d.Set("owner_group_id", rs.OwnerGroupID)
+	}
+
+	// record sets configured with records_json have their records read back
+	// into it, where suppressEquivalentRecordsJSON ignores order and formatting
+	if d.Get("records_json").(string) != "" {
+		recordsJSON, err := json.Marshal(rs.Records)
+		if err != nil {
+			return err
+		}
+		d.Set("records_json", string(recordsJSON))
+	} else {
+		if rs.Type == "CNAME" && len(rs.Records) == 1 {
+			d.Set("record_cname", rs.Records[0].CName)
+		}
+		setRecords(d, rs)
+	}
+	d.Set("updated", rs.Updated)
+	d.Set("records", flattenRecords(rs.Records))
+
+	z, err := client.Zone(d.Get("zone_id").(string))
+	if err != nil {
+		return zoneAccessError(err, d.Get("zone_id").(string))
+	}
+
+	d.Set("zone_name", z.Name)
 
 	return nil
 }
 
-func resourceVinylDNSRecordSetUpdate(d *schema.ResourceData, meta interface{}) error {
-	log.Printf("[INFO] Updating vinyldns record set: %s", d.Id())
-	records, err := records(d)
+// resourceVinylDNSRecordSetImport imports a record set by an ID of the form
+// zone_id:record_set_id. Everything else, type included, is read from vinyldns.
+func resourceVinylDNSRecordSetImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	zoneID, recordSetID, err := parseRecordSetImportID(d.Id())
 	if err != nil {
-		return err
+		return nil, err
 	}
-	updated, err := meta.(*vinyldns.Client).RecordSetUpdate(&vinyldns.RecordSet{
-		Name:    d.Get("name").(string),
-		ID:      d.Id(),
-		ZoneID:  d.Get("zone_id").(string),
-		Type:    d.Get("type").(string),
-		TTL:     d.Get("ttl").(int),
-		Records: records,
-	})
+
+	d.SetId(recordSetID)
+	d.Set("zone_id", zoneID)
+	d.Set("zone_id_from_default", false)
+	d.Set("reconcile_on_timeout", false)
+	d.Set("retry_failed_changes", 0)
+	d.Set("verify_propagation", false)
+	d.Set("propagation_timeout", "5m")
+	d.Set("preserve_unmanaged_fields", false)
+
+	rs, err := meta.(*Config).Client.RecordSet(zoneID, recordSetID)
 	if err != nil {
-		return err
+		return nil, zoneAccessError(err, zoneID)
 	}
 
-	err = waitUntilRecordSetDeployed(d, meta, updated.ChangeID)
+	// record sets of types without record_* arguments can only be configured
+	// with records_json, so it's imported for them as well
+	recordsJSON, err := importedRecordsJSON(rs)
 	if err != nil {
-		return err
+		return nil, err
 	}
+	d.Set("records_json", recordsJSON)
 
-	return resourceVinylDNSRecordSetRead(d, meta)
+	log.Printf("[INFO] Imported vinyldns record set %s of type %s with ttl %d and records %s; "+
+		"run terraform plan to compare it with its configuration before applying; %s",
+		rs.Name, rs.Type, rs.TTL, recordValuesSummary(rs), logFields("zone_id", zoneID, "record_set_id", recordSetID))
+
+	return []*schema.ResourceData{d}, nil
 }
 
-func resourceVinylDNSRecordSetDelete(d *schema.ResourceData, meta interface{}) error {
-	log.Printf("[INFO] Deleting vinyldns record set: %s", d.Id())
+// hasRecordArguments reports whether record sets of the type are configured
+// with record_* arguments, rather than only with records_json.
+func hasRecordArguments(recordType string) bool {
+	switch recordType {
+	case "A", "AAAA", "CNAME", "MX", "NS", "PTR", "TXT":
+		return true
+	}
+
+	return false
+}
+
+// importedRecordsJSON returns the records_json an imported record set is
+// configured with: its records, for types without record_* arguments, or
+// nothing for the rest, whose records are read into those arguments.
+func importedRecordsJSON(rs vinyldns.RecordSet) (string, error) {
+	if hasRecordArguments(rs.Type) {
+		return "", nil
+	}
 
-	deleted, err := meta.(*vinyldns.Client).RecordSetDelete(d.Get("zone_id").(string), d.Id())
+	b, err := json.Marshal(rs.Records)
 	if err != nil {
-		return err
+		return "", err
+	}
+
+	return string(b), nil
+}
+
+// recordValuesSummary returns the record set's records as JSON, for logging.
+func recordValuesSummary(rs vinyldns.RecordSet) string {
+	b, err := json.Marshal(rs.Records)
+	if err != nil {
+		return fmt.Sprintf("%v", rs.Records)
+	}
+
+	return string(b)
+}
+
+// suppressEquivalentRecordsJSON treats records_json holding the same records,
+// in any order and however it's formatted, as unchanged.
+func suppressEquivalentRecordsJSON(k, old, new string, d *schema.ResourceData) bool {
+	if old == "" || new == "" {
+		return false
 	}
 
-	err = waitUntilRecordSetDeployed(d, meta, deleted.ChangeID)
+	oldRecords, err := parseRecordsJSON(old)
 	if err != nil {
-		return err
+		return false
 	}
 
-	d.SetId("")
+	newRecords, err := parseRecordsJSON(new)
+	if err != nil {
+		return false
+	}
 
-	return nil
+	return recordsMatch(oldRecords, newRecords)
 }
 
-func records(d *schema.ResourceData) ([]vinyldns.Record, error) {
-	recordType := d.Get("type").(string)
+// suppressImportedHostDiff treats a host naming the record set an imported
+// record set already has as unchanged, rather than replacing it.
+func suppressImportedHostDiff(k, old, new string, d *schema.ResourceData) bool {
+	if d.Id() == "" || old != "" || new == "" {
+		return false
+	}
 
-	// SOA records are currently read-only and cannot be created, updated or deleted by vinyldns
-	if recordType == "SOA" {
-		return []vinyldns.Record{}, errors.New(recordType + " records are not currently supported by vinyldns")
+	name, err := reverseZoneRecordName(d.Get("zone_name").(string), new)
+
+	return err == nil && name == d.Get("name").(string)
+}
+
+// suppressImportedTTLDurationDiff treats a ttl_duration of the ttl an imported
+// record set already has as unchanged.
+func suppressImportedTTLDurationDiff(k, old, new string, d *schema.ResourceData) bool {
+	if d.Id() == "" || old != "" || new == "" {
+		return false
 	}
 
-	if recordType == "CNAME" {
-		cname := d.Get("record_cname").(string)
+	ttl, err := ttlSeconds(new)
 
-		if string(cname[len(cname)-1:]) != "." {
-			return []vinyldns.Record{}, errors.New("record_cname must end in trailing '.'")
-		}
+	return err == nil && ttl == d.Get("ttl").(int)
+}
 
-		return []vinyldns.Record{
-			vinyldns.Record{
-				CName: cname,
-			},
-		}, nil
+// recordSetZoneID sets and returns the zone a record set is created in: its
+// zone_id, which planDefaultZoneID has already defaulted for most, or failing
+// that, the provider's default_zone_id.
+func recordSetZoneID(d *schema.ResourceData, meta interface{}) (string, error) {
+	zoneID, err := defaultedZoneID(d.Get("zone_id").(string), meta)
+	if err != nil {
+		return "", err
 	}
 
-	if recordType == "TXT" {
-		return []vinyldns.Record{
-			vinyldns.Record{
-				Text: d.Get("record_text").(string),
-			},
-		}, nil
+	d.Set("zone_id_from_default", d.Get("zone_id_from_default").(bool) || d.Get("zone_id").(string) == "")
+	d.Set("zone_id", zoneID)
+
+	return zoneID, nil
+}
+
+// defaultedZoneID returns zoneID, or failing that, the provider's
+// default_zone_id.
+func defaultedZoneID(zoneID string, meta interface{}) (string, error) {
+	if zoneID == "" {
+		zoneID = meta.(*Config).DefaultZoneID
 	}
 
-	if recordType == "NS" {
-		return nsRecordSets(stringSetToStringSlice(d.Get("record_nsdnames").(*schema.Set))), nil
+	if zoneID == "" {
+		return "", errors.New("zone_id must be set on the record set or default_zone_id on the provider")
 	}
 
-	return addressRecordSets(stringSetToStringSlice(d.Get("record_addresses").(*schema.Set))), nil
+	return zoneID, nil
 }
 
-func addressRecordSets(addresses []string) []vinyldns.Record {
-	records := []vinyldns.Record{}
-	recordsCount := len(addresses)
+// planDefaultZoneID plans record sets configured without a zone_id in the
+// provider's default_zone_id, so that the checks made during plan see their
+// zone, and replaces those that took their zone from it when it changes. A
+// zone_id configured later takes over from the default.
+func planDefaultZoneID(d *schema.ResourceDiff, meta interface{}) error {
+	defaultZoneID := meta.(*Config).DefaultZoneID
+	if defaultZoneID == "" {
+		return nil
+	}
 
-	for i := 0; i < recordsCount; i++ {
-		records = append(records, vinyldns.Record{
-			Address: removeBrackets(addresses[i]),
-		})
+	if d.Id() == "" {
+		if d.Get("zone_id").(string) != "" {
+			return nil
+		}
+
+		if err := d.SetNew("zone_id", defaultZoneID); err != nil {
+			return err
+		}
+
+		return d.SetNew("zone_id_from_default", true)
 	}
 
-	return records
+	if !d.Get("zone_id_from_default").(bool) {
+		return nil
+	}
+
+	if d.HasChange("zone_id") {
+		return d.SetNew("zone_id_from_default", false)
+	}
+
+	if d.Get("zone_id").(string) != defaultZoneID {
+		log.Printf("[INFO] default_zone_id changed to %s; replacing record set %s, in zone %s, in it", defaultZoneID, d.Id(), d.Get("zone_id"))
+		return d.SetNew("zone_id", defaultZoneID)
+	}
+
+	return nil
 }
 
-func nsRecordSets(nsdnames []string) []vinyldns.Record {
-	records := []vinyldns.Record{}
-	recordsCount := len(nsdnames)
+func parseRecordSetImportID(id string) (string, string, error) {
+	parts := strings.SplitN(id, ":", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("record set import IDs take the form zone_id:record_set_id; got %q", id)
+	}
 
-	for i := 0; i < recordsCount; i++ {
-		records = append(records, vinyldns.Record{
-			NSDName: nsdnames[i],
-		})
+	return parts[0], parts[1], nil
+}
+
+// setRecords sets the record_* arguments matching the record set's type from
+// its records, so that drift in them, and imported record sets, are planned.
+func setRecords(d *schema.ResourceData, rs vinyldns.RecordSet) {
+	values := func(value func(r vinyldns.Record) string) []interface{} {
+		vs := []interface{}{}
+		for _, r := range rs.Records {
+			vs = append(vs, value(r))
+		}
+
+		return vs
 	}
 
-	return records
+	switch rs.Type {
+	case "A", "AAAA":
+		d.Set("record_addresses", values(func(r vinyldns.Record) string { return r.Address }))
+	case "NS":
+		d.Set("record_nsdnames", values(func(r vinyldns.Record) string { return r.NSDName }))
+	case "PTR":
+		d.Set("record_ptrdnames", values(func(r vinyldns.Record) string { return r.PTRDName }))
+	case "MX":
+		mxs := []interface{}{}
+		for _, r := range rs.Records {
+			mxs = append(mxs, map[string]interface{}{
+				"preference": r.Preference,
+				"exchange":   r.Exchange,
+			})
+		}
+		d.Set("record_mx", mxs)
+	case "TXT":
+		// record sets still using the deprecated record_text keep it
+		// alongside record_texts
+		d.Set("record_texts", values(func(r vinyldns.Record) string { return r.Text }))
+	}
 }
 
-func stringSetToStringSlice(stringSet *schema.Set) []string {
-	ret := []string{}
-	if stringSet == nil {
-		return ret
+// recordStringFields and recordIntFields are the attributes of the computed
+// records, one for each field of a vinyldns.Record.
+var (
+	recordStringFields = []string{"address", "cname", "exchange", "nsdname", "ptrdname", "mname", "rname", "text", "target", "fingerprint"}
+	recordIntFields    = []string{"preference", "serial", "refresh", "retry", "expire", "minimum", "priority", "weight", "port", "algorithm", "type"}
+)
+
+func recordResource() *schema.Resource {
+	fields := map[string]*schema.Schema{}
+	for _, k := range recordStringFields {
+		fields[k] = &schema.Schema{
+			Type:     schema.TypeString,
+			Computed: true,
+		}
 	}
-	for _, envVal := range stringSet.List() {
-		ret = append(ret, envVal.(string))
+	for _, k := range recordIntFields {
+		fields[k] = &schema.Schema{
+			Type:     schema.TypeInt,
+			Computed: true,
+		}
 	}
-	return ret
+
+	return &schema.Resource{Schema: fields}
 }
 
-func waitUntilRecordSetDeployed(d *schema.ResourceData, meta interface{}, changeID string) error {
-	stateConf := &resource.StateChangeConf{
-		Pending:      []string{"Pending", ""},
-		Target:       []string{"Complete"},
-		Refresh:      recordSetStateRefreshFunc(d, meta, changeID),
-		Timeout:      30 * time.Minute,
-		Delay:        500 * time.Millisecond,
-		MinTimeout:   15 * time.Second,
-		PollInterval: 500 * time.Millisecond,
+// flattenRecords returns records as the computed records attribute holds them,
+// with every field of each record whether or not its type uses it.
+func flattenRecords(records []vinyldns.Record) []map[string]interface{} {
+	flattened := []map[string]interface{}{}
+	for _, r := range records {
+		flattened = append(flattened, map[string]interface{}{
+			"address":     r.Address,
+			"cname":       r.CName,
+			"preference":  r.Preference,
+			"exchange":    r.Exchange,
+			"nsdname":     r.NSDName,
+			"ptrdname":    r.PTRDName,
+			"mname":       r.MName,
+			"rname":       r.RName,
+			"serial":      r.Serial,
+			"refresh":     r.Refresh,
+			"retry":       r.Retry,
+			"expire":      r.Expire,
+			"minimum":     r.Minimum,
+			"text":        r.Text,
+			"priority":    r.Priority,
+			"weight":      r.Weight,
+			"port":        r.Port,
+			"target":      r.Target,
+			"algorithm":   r.Algorithm,
+			"type":        r.Type,
+			"fingerprint": r.Fingerprint,
+		})
 	}
 
-	_, err := stateConf.WaitForState()
-	return err
+	return flattened
 }
 
-func recordSetStateRefreshFunc(d *schema.ResourceData, meta interface{}, changeID string) resource.StateRefreshFunc {
-	return func() (interface{}, string, error) {
-		log.Printf("[INFO] waiting for %v Complete status", d.Id())
-		rsc, err := meta.(*vinyldns.Client).RecordSetChange(d.Get("zone_id").(string), d.Id(), changeID)
-		if err != nil {
-			if dErr, ok := err.(*vinyldns.Error); ok {
-				if dErr.ResponseCode == http.StatusNotFound {
-					return nil, "Pending", nil
-				}
+// recordSetAPIFields are the arguments sent to vinyldns when a record set is
+// updated; the rest live only in terraform state.
+var recordSetAPIFields = []string{
+	"type", "ttl", "ttl_duration", "owner_group_id", "record_addresses", "record_nsdnames", "record_ptrdnames",
+	"record_mx", "record_cname", "record_text", "record_texts", "records_json",
+}
 
-				log.Printf("[ERROR] %#v", err)
-				return nil, "", err
-			}
+func resourceVinylDNSRecordSetUpdate(d *schema.ResourceData, meta interface{}) error {
+	log.Printf("[INFO] Updating vinyldns record set; %s", logFields("zone_id", d.Get("zone_id").(string), "record_set_id", d.Id()))
 
-			log.Printf("[ERROR] %#v", err)
-			return nil, "", err
+	changed := false
+	for _, k := range recordSetAPIFields {
+		changed = changed || d.HasChange(k)
+	}
+
+	if !changed {
+		log.Printf("[DEBUG] Only state-only arguments of vinyldns record set changed; not submitting a change; %s", logFields("record_set_id", d.Id()))
+		return nil
+	}
+
+	records, err := records(d, meta)
+	if err != nil {
+		return err
+	}
+	client := meta.(*Config).Client
+	existing, err := client.RecordSet(d.Get("zone_id").(string), d.Id())
+	if err != nil {
+		return zoneAccessError(err, d.Get("zone_id").(string))
+	}
+
+	if err := checkRecordSetVersion(d.Get("updated").(string), existing.Updated); err != nil {
+		log.Printf("[WARN] %s; re-reading record set %s", err, d.Id())
+		if rErr := resourceVinylDNSRecordSetRead(d, meta); rErr != nil {
+			return rErr
 		}
 
-		if rsc.Status == "Failed" {
-			err = errors.New("record set status Failed")
-			log.Printf("[ERROR] record set status Failed: %#v", err)
-			return rsc, rsc.Status, err
+		return err
+	}
+
+	rs := &vinyldns.RecordSet{}
+
+	// start from the record set as it exists in vinyldns so that fields this
+	// provider doesn't model survive the update
+	if d.Get("preserve_unmanaged_fields").(bool) {
+		preserved := existing
+		rs = &preserved
+	}
+
+	rs.Name = d.Get("name").(string)
+	rs.ID = d.Id()
+	rs.ZoneID = d.Get("zone_id").(string)
+	rs.Type = d.Get("type").(string)
+	rs.TTL = d.Get("ttl").(int)
+	// a preserved record set keeps the owner group vinyldns gave it unless one
+	// is configured
+	if id := ownerGroupID(d); id != "" || !d.Get("preserve_unmanaged_fields").(bool) {
+		rs.OwnerGroupID = id
+	}
+	rs.Records = records
+
+	// set normalization can plan a change to values vinyldns already holds; a
+	// change submitted for them would only add noise to the change history
+	if !recordSetUpdateNeeded(existing, rs) {
+		log.Printf("[INFO] vinyldns record set already matches its configuration; not submitting a change; %s", logFields("zone_id", rs.ZoneID, "record_set_id", d.Id()))
+		return resourceVinylDNSRecordSetRead(d, meta)
+	}
+
+	added, removed := recordsDiff(existing.Records, rs.Records)
+	log.Printf("[INFO] Updating vinyldns record set records; %s added=%s removed=%s",
+		logFields("zone_id", rs.ZoneID, "record_set_id", d.Id()), recordsString(added), recordsString(removed))
+
+	err = submitRecordSetChange(d.Get("retry_failed_changes").(int), func() (string, error) {
+		updated, err := client.RecordSetUpdate(rs)
+		if err != nil {
+			return "", recordSetAccessError(err, rs.ZoneID)
 		}
+		log.Printf("[DEBUG] Submitted vinyldns record set change; %s", logFields("zone_id", rs.ZoneID, "record_set_id", d.Id(), "change_id", updated.ChangeID))
 
-		return rsc, rsc.Status, nil
+		return updated.ChangeID, nil
+	}, func(changeID string) error {
+		return waitUntilRecordSetDeployed(d, meta, changeID)
+	})
+	if err != nil {
+		return err
 	}
+	meta.(*Config).Metrics.inc(metricRecordSetsUpdated)
+
+	if err := verifyRecordSetPropagation(d, meta, rs); err != nil {
+		return err
+	}
+
+	return resourceVinylDNSRecordSetRead(d, meta)
+}
+
+func resourceVinylDNSRecordSetDelete(d *schema.ResourceData, meta interface{}) error {
+	log.Printf("[INFO] Deleting vinyldns record set; %s", logFields("zone_id", d.Get("zone_id").(string), "record_set_id", d.Id()))
+
+	if meta.(*Config).VerifyDeletePermission {
+		if err := checkDeletePermission(meta, d.Get("zone_id").(string), d.Id()); err != nil {
+			return err
+		}
+	}
+
+	err := submitRecordSetChange(d.Get("retry_failed_changes").(int), func() (string, error) {
+		deleted, err := meta.(*Config).Client.RecordSetDelete(d.Get("zone_id").(string), d.Id())
+		if err != nil {
+			return "", err
+		}
+		log.Printf("[DEBUG] Submitted vinyldns record set change; %s", logFields("zone_id", d.Get("zone_id").(string), "record_set_id", d.Id(), "change_id", deleted.ChangeID))
+
+		return deleted.ChangeID, nil
+	}, func(changeID string) error {
+		return waitUntilRecordSetDeleted(d, meta, changeID)
+	})
+	if err != nil {
+		return err
+	}
+	meta.(*Config).Metrics.inc(metricRecordSetsDeleted)
+
+	err = confirmRecordSetDeleted(meta, d.Get("zone_id").(string), d.Id())
+	if err != nil {
+		return err
+	}
+
+	d.SetId("")
+
+	return nil
+}
+
+// checkDeletePermission fails with an explanation when the provider's
+// credentials plainly can't delete the record set, so that a destroy stops
+// before submitting anything rather than partway through. Access it can't rule
+// out, such as that granted by an ACL rule to a user rather than a group, is
+// left to vinyldns to decide.
+func checkDeletePermission(meta interface{}, zoneID, recordSetID string) error {
+	client := meta.(*Config).Client
+
+	z, err := client.Zone(zoneID)
+	if err != nil {
+		return zoneAccessError(err, zoneID)
+	}
+
+	rs, err := client.RecordSet(zoneID, recordSetID)
+	if err != nil {
+		// a record set already gone is handled by the deletion itself
+		log.Printf("[WARN] unable to read record set to verify it may be deleted: %s; %s", err, logFields("zone_id", zoneID, "record_set_id", recordSetID))
+		return nil
+	}
+
+	memberOf, err := meta.(*Config).memberGroupIDs()
+	if err != nil {
+		return fmt.Errorf("unable to list the groups of the provider's credentials to verify record set %s may be deleted: %s", recordSetID, err)
+	}
+
+	if deletePermitted(z, rs, memberOf) {
+		return nil
+	}
+
+	return fmt.Errorf("not authorized to delete %s record set %s (%s) in zone %s: the provider's credentials aren't in the zone's admin group %s, "+
+		"the record set's owner group, or any group an ACL rule grants Delete access to it. Add them to one of those groups, "+
+		"grant one of their groups Delete access with vinyldns_zone_acl, or unset verify_delete_permission for credentials with access "+
+		"vinyldns grants otherwise, such as super users", rs.Type, rs.Name, recordSetID, z.Name, z.AdminGroupID)
+}
+
+// deletePermitted reports whether members of the groups in memberOf may be
+// able to delete the record set from the zone. It errs towards permitting:
+// ACL rules are considered without their precedence, and rules naming a
+// user, or with a record mask that doesn't compile, are taken to apply.
+func deletePermitted(z vinyldns.Zone, rs vinyldns.RecordSet, memberOf map[string]bool) bool {
+	if memberOf[z.AdminGroupID] {
+		return true
+	}
+
+	// record sets in shared zones belong to their owner group, or when they
+	// have none, to whoever claims them
+	if z.Shared && (rs.OwnerGroupID == "" || memberOf[rs.OwnerGroupID]) {
+		return true
+	}
+
+	if z.ACL == nil {
+		return false
+	}
+
+	for _, rule := range z.ACL.Rules {
+		if rule.AccessLevel != "Delete" || !aclRuleCovers(rule, rs) {
+			continue
+		}
+
+		// a rule naming neither a user nor a group applies to everyone
+		if rule.UserID != "" || rule.GroupID == "" || memberOf[rule.GroupID] {
+			return true
+		}
+	}
+
+	return false
+}
+
+// aclRuleCovers reports whether the rule's record types and record mask take
+// in the record set.
+func aclRuleCovers(rule vinyldns.ACLRule, rs vinyldns.RecordSet) bool {
+	if len(rule.RecordTypes) > 0 {
+		covered := false
+		for _, t := range rule.RecordTypes {
+			if t == rs.Type {
+				covered = true
+			}
+		}
+
+		if !covered {
+			return false
+		}
+	}
+
+	if rule.RecordMask == "" {
+		return true
+	}
+
+	mask, err := regexp.Compile("^(?:" + rule.RecordMask + ")$")
+
+	return err != nil || mask.MatchString(rs.Name)
+}
+
+func resourceVinylDNSRecordSetCustomizeDiff(d *schema.ResourceDiff, meta interface{}) error {
+	if err := planDefaultZoneID(d, meta); err != nil {
+		return err
+	}
+
+	recordType := d.Get("type").(string)
+
+	if d.NewValueKnown("ttl_duration") && d.Get("ttl_duration").(string) != "" {
+		ttl, err := ttlSeconds(d.Get("ttl_duration").(string))
+		if err != nil {
+			return err
+		}
+
+		if err := d.SetNew("ttl", ttl); err != nil {
+			return err
+		}
+	}
+
+	// record_texts mirrors record_text, so it changes along with it
+	if d.HasChange("record_text") && d.Get("record_text").(string) != "" {
+		if err := d.SetNewComputed("record_texts"); err != nil {
+			return err
+		}
+	}
+
+	usesJSON := !d.NewValueKnown("records_json") || d.Get("records_json").(string) != ""
+
+	if field, ok := planValidatedRecordFields[recordType]; ok && d.NewValueKnown("type") && d.NewValueKnown(field) && !usesJSON {
+		if _, err := records(d, meta); err != nil {
+			return err
+		}
+	}
+
+	if d.NewValueKnown("zone_id") && d.NewValueKnown("created_by_group") && d.Get("created_by_group").(string) != "" {
+		z, err := meta.(*Config).planZone(d.Get("zone_id").(string))
+		if err != nil {
+			return zoneAccessError(err, d.Get("zone_id").(string))
+		}
+
+		if err := rejectCreatedByGroup(d.Get("created_by_group").(string), z); err != nil {
+			return err
+		}
+	}
+
+	// names are relative to the zone, so a name already qualified with it would
+	// be qualified twice; only a name with a dot in it can be
+	if d.NewValueKnown("name") && strings.Contains(d.Get("name").(string), ".") && d.NewValueKnown("zone_id") && d.Get("zone_id").(string) != "" {
+		name := d.Get("name").(string)
+		z, err := meta.(*Config).planZone(d.Get("zone_id").(string))
+		if err != nil {
+			log.Printf("[WARN] unable to read zone %s to check the name of record set %s: %s", d.Get("zone_id"), name, err)
+		} else if relative, ok := redundantZoneSuffix(name, z.Name); ok {
+			log.Printf("[WARN] name %s of record set already ends in its zone %s, so vinyldns may qualify it as %s.%s; "+
+				"record set names are relative to the zone, so use %s instead", name, z.Name, strings.TrimSuffix(name, "."), z.Name, relative)
+		}
+	}
+
+	if recordType == "CNAME" && d.NewValueKnown("zone_id") && d.Get("zone_id").(string) != "" {
+		name := d.Get("name").(string)
+		// only the zone name itself, or @, can name the apex
+		checkApex := d.NewValueKnown("name") && (name == "@" || strings.Contains(name, "."))
+		// a target interpolated from another resource is unknown here, and
+		// already depends on it
+		checkTarget := d.HasChange("record_cname") && d.NewValueKnown("record_cname") && d.Get("record_cname").(string) != ""
+
+		if checkApex || checkTarget {
+			z, err := meta.(*Config).planZone(d.Get("zone_id").(string))
+			if err != nil {
+				log.Printf("[WARN] unable to read zone %s to check CNAME record set %s: %s", d.Get("zone_id"), name, err)
+			} else {
+				if checkApex {
+					if err := rejectApexCNAME(name, z.Name); err != nil {
+						return err
+					}
+				}
+
+				if checkTarget {
+					warnOnDanglingCNAME(d, meta, z)
+				}
+			}
+		}
+	}
+
+	// TTLs aren't per record, which isn't obvious from a list of name servers
+	if recordType == "NS" && d.HasChange("record_nsdnames") && d.NewValueKnown("record_nsdnames") {
+		if n := d.Get("record_nsdnames").(*schema.Set).Len(); n > 1 {
+			log.Printf("[WARN] the %d name servers of NS record set %s all share its ttl of %d; as in DNS, a record set has "+
+				"one ttl for all of its records", n, d.Get("name").(string), d.Get("ttl").(int))
+		}
+	}
+
+	if recordType == "NS" && d.NewValueKnown("zone_id") && d.Get("zone_id").(string) != "" {
+		zoneID := d.Get("zone_id").(string)
+		z, err := meta.(*Config).planZone(zoneID)
+		if err != nil {
+			log.Printf("[WARN] unable to read zone %s to check NS record set: %s", zoneID, err)
+			return nil
+		}
+
+		if d.NewValueKnown("name") {
+			if err := rejectApexNS(d.Get("name").(string), z.Name); err != nil {
+				return err
+			}
+		}
+
+		warnOnDivergentNSTTL(d, meta, z)
+		warnOnMissingGlue(d, meta, z)
+	}
+
+	return nil
+}
+
+// isApex reports whether the record set name names the apex of zone zoneName.
+func isApex(name, zoneName string) bool {
+	return name == "@" || strings.EqualFold(strings.TrimSuffix(name, "."), strings.TrimSuffix(zoneName, "."))
+}
+
+// rejectApexNS returns an error for NS record sets named for the zone apex, which
+// vinyldns manages itself from the zone's name servers; left to the server, such
+// changes are rejected with a message that doesn't explain why.
+func rejectApexNS(name, zoneName string) error {
+	if isApex(name, zoneName) {
+		return fmt.Errorf("NS record set %s is at the apex of zone %s; vinyldns manages apex NS records itself, so they cannot be created, updated or deleted here", name, zoneName)
+	}
+
+	return nil
+}
+
+// rejectApexCNAME returns an error for CNAME record sets named for the zone
+// apex, which DNS doesn't allow, with the alternatives vinyldns offers.
+func rejectApexCNAME(name, zoneName string) error {
+	if isApex(name, zoneName) {
+		return fmt.Errorf("CNAME record set %s is at the apex of zone %s; DNS doesn't allow a CNAME alongside the SOA and NS records "+
+			"every zone apex has. vinyldns has no ALIAS or ANAME records to flatten it, so instead create A and AAAA record sets "+
+			"with the target's addresses at the apex, or put the CNAME on a name within the zone, such as www", name, zoneName)
+	}
+
+	return nil
+}
+
+// redundantZoneSuffix reports whether name is qualified with zoneName, and if so
+// returns it relative to the zone. A name that is the zone name itself names the
+// apex, and isn't redundant.
+func redundantZoneSuffix(name, zoneName string) (string, bool) {
+	name = strings.ToLower(strings.TrimSuffix(name, "."))
+	suffix := "." + strings.ToLower(strings.TrimSuffix(zoneName, "."))
+	if suffix == "." || !strings.HasSuffix(name, suffix) || name == suffix[1:] {
+		return "", false
+	}
+
+	return strings.TrimSuffix(name, suffix), true
+}
+
+// rejectCreatedByGroup returns an error for a created_by_group set on a record
+// set in a zone that isn't shared. vinyldns only records a group against record
+// sets in shared zones, so rather than silently drop the group, say so.
+func rejectCreatedByGroup(group string, z vinyldns.Zone) error {
+	if group == "" || z.Shared {
+		return nil
+	}
+
+	return fmt.Errorf("created_by_group %s is not supported in zone %s; vinyldns only attributes record sets to a group in shared zones, "+
+		"and record sets in other zones belong to the zone's admin group", group, z.Name)
+}
+
+// ownerGroupID returns the group to send as the record set's owner: its
+// owner_group_id, or failing that, the group it was created by.
+func ownerGroupID(d *schema.ResourceData) string {
+	if id := d.Get("owner_group_id").(string); id != "" {
+		return id
+	}
+
+	return d.Get("created_by_group").(string)
+}
+
+// recordSetUpdateNeeded reports whether updating existing to rs would change
+// anything vinyldns holds.
+func recordSetUpdateNeeded(existing vinyldns.RecordSet, rs *vinyldns.RecordSet) bool {
+	return existing.Name != rs.Name ||
+		existing.Type != rs.Type ||
+		existing.TTL != rs.TTL ||
+		existing.OwnerGroupID != rs.OwnerGroupID ||
+		!recordsMatch(existing.Records, rs.Records)
+}
+
+// checkRecordSetVersion returns an error when a record set has been updated since
+// terraform last read it, rather than let an update clobber that change. State
+// written before the updated timestamp was tracked is never considered stale.
+func checkRecordSetVersion(read, current string) error {
+	if read == "" || read == current {
+		return nil
+	}
+
+	return fmt.Errorf("record set was modified outside of terraform at %s, after it was last read at %s; review the refreshed record set and apply again", current, read)
+}
+
+// ttlSeconds converts a duration such as "1h" or "5m" to a TTL in seconds.
+func ttlSeconds(v string) (int, error) {
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return 0, err
+	}
+
+	if d < time.Second || d%time.Second != 0 {
+		return 0, fmt.Errorf("%s is not a whole number of seconds", v)
+	}
+
+	return int(d / time.Second), nil
+}
+
+// validateTTLDuration ensures the value is a duration of one or more whole seconds.
+func validateTTLDuration(v interface{}, k string) (ws []string, es []error) {
+	value := v.(string)
+
+	if _, err := ttlSeconds(value); err != nil {
+		es = append(es, fmt.Errorf("%q must be a duration of whole seconds such as \"5m\" or \"1h\"; got %q", k, value))
+	}
+
+	return
+}
+
+// requireAddresses ensures address record sets are given at least one address;
+// otherwise vinyldns rejects the change with an unhelpful error.
+func requireAddresses(recordType string, count int) error {
+	if (recordType == "A" || recordType == "AAAA") && count == 0 {
+		return fmt.Errorf("%s record sets require at least one address; set record_addresses", recordType)
+	}
+
+	return nil
+}
+
+// nsTTLDivergenceFactor is how many times larger or smaller than its zone's SOA
+// TTL an NS record set's TTL may be before a warning is logged.
+const nsTTLDivergenceFactor = 2
+
+// warnOnDivergentNSTTL logs a warning when an NS record set's TTL strays far from
+// the TTL of its zone's SOA record set. It never fails the plan.
+func warnOnDivergentNSTTL(d *schema.ResourceDiff, meta interface{}, z vinyldns.Zone) {
+	if !d.NewValueKnown("ttl") {
+		return
+	}
+
+	ttl := d.Get("ttl").(int)
+	if ttl == 0 {
+		return
+	}
+
+	rss, err := meta.(*Config).planZoneRecordSets(z.ID)
+	if err != nil {
+		log.Printf("[WARN] unable to read SOA record set of zone %s to compare NS record set ttl: %s", z.ID, err)
+		return
+	}
+
+	for _, rs := range rss {
+		if rs.Type != "SOA" {
+			continue
+		}
+
+		if ttl > rs.TTL*nsTTLDivergenceFactor || ttl*nsTTLDivergenceFactor < rs.TTL {
+			log.Printf("[WARN] NS record set %s ttl %d differs significantly from zone %s SOA ttl %d; consider keeping them consistent",
+				d.Get("name").(string), ttl, z.Name, rs.TTL)
+		}
+
+		return
+	}
+}
+
+// warnOnMissingGlue logs a warning for each name server of an NS record set
+// that lies within the record set's zone but has no A or AAAA record set there
+// for resolvers to find it by. It never fails the plan, as the glue may be
+// being created in the same apply.
+func warnOnMissingGlue(d *schema.ResourceDiff, meta interface{}, z vinyldns.Zone) {
+	if !d.NewValueKnown("record_nsdnames") {
+		return
+	}
+
+	nsdnames := stringSetToStringSlice(d.Get("record_nsdnames").(*schema.Set))
+	names := inZoneNameServers(nsdnames, z.Name)
+	if len(names) == 0 {
+		return
+	}
+
+	rss, err := meta.(*Config).planZoneRecordSets(z.ID)
+	if err != nil {
+		log.Printf("[WARN] unable to read record sets of zone %s to check NS record set glue: %s", z.ID, err)
+		return
+	}
+
+	for _, name := range names {
+		glued := false
+		for _, rs := range rss {
+			if rs.Name == name && (rs.Type == "A" || rs.Type == "AAAA") {
+				glued = true
+			}
+		}
+
+		if !glued {
+			log.Printf("[WARN] name server %s.%s of NS record set %s is within zone %s, which has no A or AAAA record set named %s for it; "+
+				"unless one is created in this apply, resolvers won't be able to find the name server", name, z.Name, d.Get("name").(string), z.Name, name)
+		}
+	}
+}
+
+// warnOnDanglingCNAME warns when the target of a CNAME record set lies within
+// its zone but doesn't exist there yet. Terraform orders resources only by the
+// references between them, so a target record set written literally rather
+// than referenced may be created after the CNAME.
+func warnOnDanglingCNAME(d *schema.ResourceDiff, meta interface{}, z vinyldns.Zone) {
+	target := d.Get("record_cname").(string)
+	relative, missing, err := missingCNAMETarget(meta, z, target)
+	if err != nil {
+		log.Printf("[WARN] unable to read record sets of zone %s to check CNAME target %s: %s", z.ID, target, err)
+		return
+	}
+
+	if !missing {
+		return
+	}
+
+	log.Printf("[WARN] target %s of CNAME record set %s is within zone %s, which has no record set named %s yet; "+
+		"if it's created in this configuration, reference it from record_cname or add depends_on so that it's created first",
+		target, d.Get("name").(string), z.Name, relative)
+}
+
+// missingCNAMETarget reports whether target lies within zone z without a record
+// set of its name, which it returns relative to z. Targets outside z are never
+// missing, as z can't hold them.
+func missingCNAMETarget(meta interface{}, z vinyldns.Zone, target string) (string, bool, error) {
+	relative, ok := redundantZoneSuffix(target, z.Name)
+	if !ok {
+		return "", false, nil
+	}
+
+	rss, err := meta.(*Config).planZoneRecordSets(z.ID)
+	if err != nil {
+		return relative, false, err
+	}
+
+	for _, rs := range rss {
+		if strings.EqualFold(rs.Name, relative) {
+			return relative, false, nil
+		}
+	}
+
+	return relative, true, nil
+}
+
+// inZoneNameServers returns the names, relative to the zone, of the name
+// servers that lie within it. Only these need glue records in the zone.
+func inZoneNameServers(nsdnames []string, zoneName string) []string {
+	suffix := "." + strings.ToLower(strings.TrimSuffix(zoneName, ".")) + "."
+	names := []string{}
+
+	for _, ns := range nsdnames {
+		ns = strings.ToLower(ns)
+		if !strings.HasSuffix(ns, ".") {
+			ns += "."
+		}
+
+		if strings.HasSuffix(ns, suffix) {
+			names = append(names, strings.TrimSuffix(ns, suffix))
+		}
+	}
+
+	return names
+}
+
+// planValidatedRecordFields are the record_* arguments whose records are
+// validated during plan, by the record set types they're used with.
+var planValidatedRecordFields = map[string]string{
+	"A":     "record_addresses",
+	"AAAA":  "record_addresses",
+	"CNAME": "record_cname",
+	"NS":    "record_nsdnames",
+}
+
+// recordSetGetter is implemented by both *schema.ResourceData and
+// *schema.ResourceDiff, so records can be built at apply and at plan alike.
+type recordSetGetter interface {
+	Get(key string) interface{}
+}
+
+// records returns the record set's configured records once validated for its
+// type, refusing more of them than the provider's max_record_set_entries allows.
+func records(d recordSetGetter, meta interface{}) ([]vinyldns.Record, error) {
+	rs, err := configuredRecords(d, meta)
+	if err != nil {
+		return rs, err
+	}
+
+	if err := validateRecords(d.Get("type").(string), rs); err != nil {
+		return rs, err
+	}
+
+	return rs, checkRecordCount(len(rs), meta.(*Config).MaxRecordSetEntries)
+}
+
+func configuredRecords(d recordSetGetter, meta interface{}) ([]vinyldns.Record, error) {
+	recordType := d.Get("type").(string)
+
+	// SOA records are currently read-only and cannot be created, updated or deleted by vinyldns
+	if recordType == "SOA" {
+		return []vinyldns.Record{}, ErrUnsupportedType
+	}
+
+	if v := d.Get("records_json").(string); v != "" {
+		return parseRecordsJSON(v)
+	}
+
+	if recordType == "CNAME" {
+		cname := d.Get("record_cname").(string)
+		if meta.(*Config).CNAMEAutoTrailingDot && cname != "" && !strings.HasSuffix(cname, ".") {
+			cname += "."
+		}
+
+		return []vinyldns.Record{
+			vinyldns.Record{
+				CName: cname,
+			},
+		}, nil
+	}
+
+	if recordType == "TXT" {
+		// record_texts is computed from record_text when that's configured,
+		// so record_text takes precedence
+		text := d.Get("record_text").(string)
+		if texts := stringSetToStringSlice(d.Get("record_texts").(*schema.Set)); text == "" && len(texts) > 0 {
+			return txtRecordSets(texts), nil
+		}
+
+		return []vinyldns.Record{
+			vinyldns.Record{
+				Text: text,
+			},
+		}, nil
+	}
+
+	if recordType == "NS" {
+		nsdnames := stringSetToStringSlice(d.Get("record_nsdnames").(*schema.Set))
+		if meta.(*Config).NSPTRAutoTrailingDot {
+			nsdnames = withTrailingDots(nsdnames)
+		}
+
+		return nsRecordSets(nsdnames), nil
+	}
+
+	if recordType == "MX" {
+		return mxRecordSets(d.Get("record_mx").(*schema.Set).List()), nil
+	}
+
+	if recordType == "PTR" {
+		ptrdnames := stringSetToStringSlice(d.Get("record_ptrdnames").(*schema.Set))
+		if meta.(*Config).NSPTRAutoTrailingDot {
+			ptrdnames = withTrailingDots(ptrdnames)
+		}
+
+		return ptrRecordSets(ptrdnames), nil
+	}
+
+	if recordType == "A" || recordType == "AAAA" {
+		return addressRecordSets(recordType, stringSetToStringSlice(d.Get("record_addresses").(*schema.Set))), nil
+	}
+
+	return []vinyldns.Record{}, fmt.Errorf("%s record sets have no record_* arguments; set records_json", recordType)
+}
+
+// checkRecordCount guards against runaway generated configuration, such as a
+// mistaken CIDR expansion, creating an enormous record set.
+func checkRecordCount(count, max int) error {
+	if max > 0 && count > max {
+		return fmt.Errorf("record set has %d records, more than the %d allowed by the provider's max_record_set_entries; "+
+			"raise max_record_set_entries if this is intended", count, max)
+	}
+
+	return nil
+}
+
+// parseRecordsJSON decodes a JSON array of go-vinyldns records, rejecting fields
+// vinyldns.Record doesn't have so that typos aren't silently dropped.
+func parseRecordsJSON(v string) ([]vinyldns.Record, error) {
+	records := []vinyldns.Record{}
+
+	dec := json.NewDecoder(bytes.NewBufferString(v))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&records); err != nil {
+		return nil, fmt.Errorf("records_json must be a JSON array of records: %s", err)
+	}
+
+	if len(records) == 0 {
+		return nil, errors.New("records_json must contain at least one record")
+	}
+
+	return records, nil
+}
+
+func validateRecordsJSON(v interface{}, k string) (ws []string, es []error) {
+	if _, err := parseRecordsJSON(v.(string)); err != nil {
+		es = append(es, err)
+	}
+
+	return
+}
+
+func addressRecordSets(recordType string, addresses []string) []vinyldns.Record {
+	records := []vinyldns.Record{}
+	recordsCount := len(addresses)
+
+	for i := 0; i < recordsCount; i++ {
+		records = append(records, vinyldns.Record{
+			Address: recordAddress(recordType, addresses[i]),
+		})
+	}
+
+	return records
+}
+
+// recordAddress returns the address to send for a record of the given type.
+// Only AAAA addresses are normalized; others are sent exactly as written.
+func recordAddress(recordType, address string) string {
+	if recordType != "AAAA" {
+		return address
+	}
+
+	return normalizeAddress(address)
+}
+
+func txtRecordSets(texts []string) []vinyldns.Record {
+	records := []vinyldns.Record{}
+
+	for _, text := range texts {
+		records = append(records, vinyldns.Record{
+			Text: text,
+		})
+	}
+
+	return records
+}
+
+// hashName hashes a name server or PTR name without regard to its trailing
+// '.', so that a name written without one doesn't differ from the same name
+// as vinyldns returns it.
+func hashName(v interface{}) int {
+	return hashcode.String(strings.TrimSuffix(v.(string), "."))
+}
+
+// withTrailingDots returns names, each ending in '.'.
+func withTrailingDots(names []string) []string {
+	dotted := []string{}
+	for _, name := range names {
+		if name != "" && !strings.HasSuffix(name, ".") {
+			name += "."
+		}
+		dotted = append(dotted, name)
+	}
+
+	return dotted
+}
+
+func nsRecordSets(nsdnames []string) []vinyldns.Record {
+	records := []vinyldns.Record{}
+	recordsCount := len(nsdnames)
+
+	for i := 0; i < recordsCount; i++ {
+		records = append(records, vinyldns.Record{
+			NSDName: nsdnames[i],
+		})
+	}
+
+	return records
+}
+
+// mxRecordSets returns the MX records ordered by preference and then exchange,
+// so the same set of records is always sent the same way.
+func mxRecordSets(mxs []interface{}) []vinyldns.Record {
+	records := []vinyldns.Record{}
+
+	for _, v := range mxs {
+		mx := v.(map[string]interface{})
+		records = append(records, vinyldns.Record{
+			Preference: mx["preference"].(int),
+			Exchange:   mx["exchange"].(string),
+		})
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		if records[i].Preference != records[j].Preference {
+			return records[i].Preference < records[j].Preference
+		}
+
+		return records[i].Exchange < records[j].Exchange
+	})
+
+	return records
+}
+
+// mxRecordHash hashes both fields of an MX record, so records sharing a
+// preference but not an exchange, or vice versa, are distinct set members.
+func mxRecordHash(v interface{}) int {
+	mx := v.(map[string]interface{})
+
+	return hashcode.String(fmt.Sprintf("%d:%s", mx["preference"].(int), mx["exchange"].(string)))
+}
+
+// recordSetAccessError turns the errors vinyldns returns for record set writes
+// the caller isn't permitted into ones explaining what to do about them.
+func recordSetAccessError(err error, zoneID string) error {
+	dErr, ok := err.(*vinyldns.Error)
+	if !ok {
+		return err
+	}
+
+	body := strings.ToLower(dErr.ResponseBody)
+
+	if strings.Contains(body, "owner group") && (strings.Contains(body, "must be specified") || strings.Contains(body, "required")) {
+		return fmt.Errorf("zone %s is a shared zone, so record sets created in it need an owner; set owner_group_id to a group you belong to: %s", zoneID, err)
+	}
+
+	if dErr.ResponseCode == http.StatusForbidden {
+		return fmt.Errorf("no write access to zone %s; ask its admin group for an ACL rule granting access, or for shared zones, "+
+			"set owner_group_id to a group you belong to: %s", zoneID, err)
+	}
+
+	return err
+}
+
+// adoptableRecordSet returns the zone's existing record set matching rs, which
+// a create interrupted after vinyldns made the record set, but before terraform
+// recorded it, leaves behind. Adopting it keeps the retried create from making a
+// duplicate. A record set of the same name and type holding other data is an
+// error, as is any other clash vinyldns would refuse the create for.
+func adoptableRecordSet(meta interface{}, rs *vinyldns.RecordSet) (*vinyldns.RecordSet, error) {
+	existing, err := findRecordSet(meta, rs.ZoneID, rs.Name, rs.Type)
+	if err != nil || existing == nil {
+		return nil, err
+	}
+
+	// an unset ttl takes whatever vinyldns defaulted it to
+	if (rs.TTL == 0 || existing.TTL == rs.TTL) && recordsMatch(existing.Records, rs.Records) {
+		return existing, nil
+	}
+
+	return nil, fmt.Errorf("a %s record set named %s already exists in zone %s with different data (record set %s); "+
+		"import it with `terraform import` or remove it before applying again", rs.Type, rs.Name, rs.ZoneID, existing.ID)
+}
+
+// findRecordSet returns the zone's record set with exactly the given name and
+// type, or nil if there isn't one.
+func findRecordSet(meta interface{}, zoneID, name, recordType string) (*vinyldns.RecordSet, error) {
+	rss, err := meta.(*Config).Client.RecordSetsListAll(zoneID, vinyldns.ListFilter{
+		NameFilter: name,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range rss {
+		if rss[i].Name == name && rss[i].Type == recordType {
+			return &rss[i], nil
+		}
+	}
+
+	return nil, nil
+}
+
+// recordsMatch reports whether a and b hold the same records, in any order.
+func recordsMatch(a, b []vinyldns.Record) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	counts := map[vinyldns.Record]int{}
+	for _, r := range a {
+		counts[r]++
+	}
+	for _, r := range b {
+		if counts[r] == 0 {
+			return false
+		}
+		counts[r]--
+	}
+
+	return true
+}
+
+// recordsDiff returns the records of b that aren't in a, and those of a that
+// aren't in b, treating both as unordered.
+func recordsDiff(a, b []vinyldns.Record) (added, removed []vinyldns.Record) {
+	counts := map[vinyldns.Record]int{}
+	for _, r := range a {
+		counts[r]++
+	}
+
+	for _, r := range b {
+		if counts[r] > 0 {
+			counts[r]--
+			continue
+		}
+		added = append(added, r)
+	}
+
+	for _, r := range a {
+		if counts[r] > 0 {
+			counts[r]--
+			removed = append(removed, r)
+		}
+	}
+
+	return added, removed
+}
+
+// recordsString renders records compactly for logging, as the JSON vinyldns
+// takes them in.
+func recordsString(records []vinyldns.Record) string {
+	if len(records) == 0 {
+		return "[]"
+	}
+
+	b, err := json.Marshal(records)
+	if err != nil {
+		return fmt.Sprintf("%v", records)
+	}
+
+	return string(b)
+}
+
+func ptrRecordSets(ptrdnames []string) []vinyldns.Record {
+	records := []vinyldns.Record{}
+	recordsCount := len(ptrdnames)
+
+	for i := 0; i < recordsCount; i++ {
+		records = append(records, vinyldns.Record{
+			PTRDName: ptrdnames[i],
+		})
+	}
+
+	return records
+}
+
+// recordSetName returns the configured name, or for record sets configured by
+// host, the name the host's octets take within the record set's reverse zone.
+func recordSetName(d *schema.ResourceData, meta interface{}) (string, error) {
+	host := d.Get("host").(string)
+	if host == "" {
+		name := d.Get("name").(string)
+		if name == "" {
+			return "", errors.New("one of name or host must be set")
+		}
+
+		return name, nil
+	}
+
+	z, err := meta.(*Config).Client.Zone(d.Get("zone_id").(string))
+	if err != nil {
+		return "", err
+	}
+
+	return reverseZoneRecordName(z.Name, host)
+}
+
+// reverseZoneRecordName returns the record name of the host within an IPv4
+// reverse zone. The host holds the trailing octets of the address in their
+// usual order, so host "1.42" in zone "168.192.in-addr.arpa." is named "42.1".
+func reverseZoneRecordName(zoneName, host string) (string, error) {
+	suffix := ".in-addr.arpa."
+	if !strings.HasSuffix(zoneName, suffix) {
+		return "", fmt.Errorf("host can only be used in IPv4 reverse zones; %s is not one", zoneName)
+	}
+
+	zoneOctets := strings.Split(strings.TrimSuffix(zoneName, suffix), ".")
+	hostOctets := strings.Split(host, ".")
+	if len(zoneOctets)+len(hostOctets) != 4 {
+		return "", fmt.Errorf("host %s must have %d octet(s) to address a host within zone %s", host, 4-len(zoneOctets), zoneName)
+	}
+
+	for i, j := 0, len(hostOctets)-1; i < j; i, j = i+1, j-1 {
+		hostOctets[i], hostOctets[j] = hostOctets[j], hostOctets[i]
+	}
+
+	return strings.Join(hostOctets, "."), nil
+}
+
+// validateHostOctets ensures the value is one or more dot-separated octets.
+func validateHostOctets(v interface{}, k string) (ws []string, es []error) {
+	value := v.(string)
+
+	for _, octet := range strings.Split(value, ".") {
+		n, err := strconv.Atoi(octet)
+		if err != nil || n < 0 || n > 255 || strconv.Itoa(n) != octet {
+			es = append(es, fmt.Errorf("%q must be dot-separated octets between 0 and 255; got %q", k, value))
+			return
+		}
+	}
+
+	return
+}
+
+func stringSetToStringSlice(stringSet *schema.Set) []string {
+	ret := []string{}
+	if stringSet == nil {
+		return ret
+	}
+	for _, envVal := range stringSet.List() {
+		ret = append(ret, envVal.(string))
+	}
+	return ret
+}
+
+// zoneMissing reports whether err, returned by a record set request, is a 404
+// caused by the record set's zone no longer existing.
+func zoneMissing(meta interface{}, zoneID string, err error) (bool, error) {
+	dErr, ok := err.(*vinyldns.Error)
+	if !ok || dErr.ResponseCode != http.StatusNotFound {
+		return false, nil
+	}
+
+	exists, err := meta.(*Config).Client.ZoneExists(zoneID)
+	if err != nil {
+		return false, err
+	}
+
+	return !exists, nil
+}
+
+// recordSetZoneNotFound is the state reported while polling a record set change
+// whose zone no longer exists.
+const recordSetZoneNotFound = "ZoneNotFound"
+
+// verifyRecordSetPropagation waits, when verify_propagation is set, for the
+// records of rs, whose change vinyldns has completed, to resolve in DNS.
+func verifyRecordSetPropagation(d *schema.ResourceData, meta interface{}, rs *vinyldns.RecordSet) error {
+	if !d.Get("verify_propagation").(bool) {
+		return nil
+	}
+
+	z, err := meta.(*Config).Client.Zone(rs.ZoneID)
+	if err != nil {
+		return err
+	}
+
+	// already vetted by validateDuration
+	timeout, _ := time.ParseDuration(d.Get("propagation_timeout").(string))
+
+	resolvers := []string{}
+	for _, r := range d.Get("propagation_resolvers").([]interface{}) {
+		resolvers = append(resolvers, r.(string))
+	}
+
+	log.Printf("[INFO] Verifying vinyldns record set propagation; %s", logFields("zone_id", rs.ZoneID, "record_set_id", d.Id()))
+
+	return verifyPropagation(*rs, z.Name, resolvers, timeout)
+}
+
+// submitRecordSetChange submits a record set change and waits for vinyldns to
+// process it, resubmitting it up to retries times should vinyldns fail it,
+// such as for a momentary conflict with another change.
+func submitRecordSetChange(retries int, submit func() (string, error), wait func(changeID string) error) error {
+	for attempt := 0; ; attempt++ {
+		changeID, err := submit()
+		if err != nil {
+			return err
+		}
+
+		err = wait(changeID)
+		if err != ErrRecordSetChangeFailed || attempt == retries {
+			return err
+		}
+
+		log.Printf("[WARN] vinyldns record set change %s failed; resubmitting it (retry %d of %d)", changeID, attempt+1, retries)
+	}
+}
+
+func waitUntilRecordSetDeployed(d *schema.ResourceData, meta interface{}, changeID string) error {
+	err := waitUntilRecordSetChangeDeployed(meta, d.Get("zone_id").(string), d.Id(), changeID)
+	if _, ok := err.(*recordSetChangeTimeoutError); ok && d.Get("reconcile_on_timeout").(bool) {
+		return reconcileRecordSet(d, meta, err, false)
+	}
+
+	return err
+}
+
+func waitUntilRecordSetDeleted(d *schema.ResourceData, meta interface{}, changeID string) error {
+	err := waitUntilRecordSetChangeDeleted(meta, d.Get("zone_id").(string), d.Id(), changeID)
+	if _, ok := err.(*recordSetChangeTimeoutError); ok && d.Get("reconcile_on_timeout").(bool) {
+		return reconcileRecordSet(d, meta, err, true)
+	}
+
+	return err
+}
+
+// confirmRecordSetDeleted ensures a record set whose delete has completed is in
+// fact gone, rather than take the completed change's word for it.
+func confirmRecordSetDeleted(meta interface{}, zoneID, recordSetID string) error {
+	_, err := meta.(*Config).Client.RecordSet(zoneID, recordSetID)
+	if err == nil {
+		return fmt.Errorf("record set %s still exists after its delete completed", recordSetID)
+	}
+
+	if dErr, ok := err.(*vinyldns.Error); ok && dErr.ResponseCode == http.StatusNotFound {
+		return nil
+	}
+
+	return err
+}
+
+// recordSetChangeTimeoutError reports a record set change that vinyldns didn't
+// finish processing in time.
+type recordSetChangeTimeoutError struct {
+	changeID string
+	err      error
+}
+
+func (e *recordSetChangeTimeoutError) Error() string {
+	return fmt.Sprintf("record set change %s did not complete: %s", e.changeID, e.err)
+}
+
+// reconcileRecordSet re-reads a record set whose change timed out. If vinyldns
+// shows the change took effect anyway, the timeout is forgiven; otherwise the
+// original timeout error is returned.
+func reconcileRecordSet(d *schema.ResourceData, meta interface{}, timeoutErr error, deleting bool) error {
+	log.Printf("[WARN] %s; re-reading record set %s to reconcile its state", timeoutErr, d.Id())
+	rs, err := meta.(*Config).Client.RecordSet(d.Get("zone_id").(string), d.Id())
+
+	if deleting {
+		if dErr, ok := err.(*vinyldns.Error); ok && dErr.ResponseCode == http.StatusNotFound {
+			log.Printf("[WARN] record set %s no longer exists; treating its delete as complete", d.Id())
+			return nil
+		}
+
+		return timeoutErr
+	}
+
+	if err == nil && rs.Status == "Active" {
+		log.Printf("[WARN] record set %s is Active; treating its change as complete", d.Id())
+		return nil
+	}
+
+	return timeoutErr
+}
+
+func waitUntilRecordSetChangeDeployed(meta interface{}, zoneID, recordSetID, changeID string) error {
+	return waitForRecordSetChange(meta, zoneID, recordSetID, changeID, []string{"Complete"})
+}
+
+// waitUntilRecordSetChangeDeleted also accepts the zone itself disappearing, as a
+// record set is necessarily gone once its zone has been deleted.
+func waitUntilRecordSetChangeDeleted(meta interface{}, zoneID, recordSetID, changeID string) error {
+	return waitForRecordSetChange(meta, zoneID, recordSetID, changeID, []string{"Complete", recordSetZoneNotFound})
+}
+
+// recordSetChangeTimeout is how long a record set change may take to complete
+// before the provider gives up waiting on it; tests shorten it.
+var recordSetChangeTimeout = 30 * time.Minute
+
+func waitForRecordSetChange(meta interface{}, zoneID, recordSetID, changeID string, target []string) error {
+	stateConf := &resource.StateChangeConf{
+		Pending:      []string{"Pending"},
+		Target:       target,
+		Refresh:      recordSetStateRefreshFunc(meta, zoneID, recordSetID, changeID),
+		Timeout:      recordSetChangeTimeout,
+		Delay:        meta.(*Config).RecordPollDelay,
+		MinTimeout:   meta.(*Config).RecordPollMinTimeout,
+		PollInterval: 500 * time.Millisecond,
+	}
+
+	_, err := stateConf.WaitForState()
+	if _, ok := err.(*resource.TimeoutError); ok {
+		return &recordSetChangeTimeoutError{changeID: changeID, err: err}
+	}
+
+	return err
+}
+
+func recordSetStateRefreshFunc(meta interface{}, zoneID, recordSetID, changeID string) resource.StateRefreshFunc {
+	fields := logFields("zone_id", zoneID, "record_set_id", recordSetID, "change_id", changeID)
+	transitions := &statusTransitions{}
+
+	return func() (interface{}, string, error) {
+		log.Printf("[DEBUG] Polling vinyldns record set change; %s", fields)
+		meta.(*Config).Metrics.inc(metricPollCycles)
+		client := meta.(*Config).Client
+		rsc, err := client.RecordSetChange(zoneID, recordSetID, changeID)
+		if err != nil {
+			if dErr, ok := err.(*vinyldns.Error); ok {
+				if dErr.ResponseCode == http.StatusNotFound {
+					// a 404 may mean the change isn't visible yet or that the
+					// whole zone is gone; only the former is worth waiting on
+					exists, err := client.ZoneExists(zoneID)
+					if err != nil {
+						log.Printf("[ERROR] %#v; %s", err, fields)
+						return nil, "", err
+					}
+
+					if !exists {
+						log.Printf("[INFO] zone of record set no longer exists; %s", fields)
+						return &zoneState{State: recordSetZoneNotFound}, recordSetZoneNotFound, nil
+					}
+
+					return nil, "Pending", nil
+				}
+
+				log.Printf("[ERROR] %#v; %s", err, fields)
+				return nil, "", err
+			}
+
+			log.Printf("[ERROR] %#v; %s", err, fields)
+			return nil, "", err
+		}
+
+		log.Printf("[DEBUG] %s; %s", transitions.observe(rsc.Status, time.Now()), fields)
+
+		if rsc.Status == "" {
+			err = fmt.Errorf("record set change %s reported an empty status", changeID)
+			log.Printf("[ERROR] %s; %s", err, fields)
+			return rsc, rsc.Status, err
+		}
+
+		if rsc.Status == "Failed" {
+			err = ErrRecordSetChangeFailed
+			log.Printf("[ERROR] record set status Failed: %#v; %s", err, fields)
+			return rsc, rsc.Status, err
+		}
+
+		return rsc, rsc.Status, nil
+	}
+}
+
+// statusTransitions follows the statuses a record set change reports across
+// polls, such as Pending giving way to Complete, and when each was seen, so
+// that slow changes can be followed through TF_LOG=DEBUG.
+type statusTransitions struct {
+	polls    int
+	status   string
+	started  time.Time
+	lastPoll time.Time
+}
+
+// observe records status as seen at now and describes it: whether it changed
+// and how long after the previous poll, and the first, it was seen.
+func (s *statusTransitions) observe(status string, now time.Time) string {
+	s.polls++
+	if s.polls == 1 {
+		s.started, s.lastPoll = now, now
+	}
+
+	var transition string
+	switch {
+	case s.polls == 1:
+		transition = fmt.Sprintf("vinyldns record set change status %q", status)
+	case status != s.status:
+		transition = fmt.Sprintf("vinyldns record set change status %q -> %q", s.status, status)
+	default:
+		transition = fmt.Sprintf("vinyldns record set change status %q unchanged", status)
+	}
+
+	description := fmt.Sprintf("%s on poll %d, %s after the previous poll and %s after the first",
+		transition, s.polls, now.Sub(s.lastPoll), now.Sub(s.started))
+	s.status, s.lastPoll = status, now
+
+	return description
+}
+
+// suppressTrailingDotDiff treats names differing only by a trailing '.' as the
+// same, so a cname written without one matches the fully qualified form read
+// back from vinyldns.
+func suppressTrailingDotDiff(k, old, new string, d *schema.ResourceData) bool {
+	return old != "" && new != "" && strings.TrimSuffix(old, ".") == strings.TrimSuffix(new, ".")
+}
+
+// normalizeAddress strips brackets from and rewrites IPv6 addresses in their
+// canonical form, so equivalent spellings like [2001:DB8:0:0:0:0:0:1] and
+// 2001:db8::1 agree. Any other value is left as it is, brackets and all.
+func normalizeAddress(v interface{}) string {
+	address := v.(string)
+	if unbracketed := removeBrackets(address); strings.Contains(unbracketed, ":") && net.ParseIP(unbracketed) != nil {
+		address = unbracketed
+	}
+
+	ip := net.ParseIP(address)
+	if ip == nil || ip.To4() != nil {
+		return address
+	}
+
+	return ip.String()
 }
 
 // vinyldns responds 400 to IPv6 addresses represented within `[` `]`
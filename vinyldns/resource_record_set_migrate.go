@@ -0,0 +1,58 @@
+/*
+Copyright 2018 Comcast Cable Communications Management, LLC
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vinyldns
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/hashicorp/terraform/helper/hashcode"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+// resourceVinylDNSRecordSetMigrateState upgrades record set state written by
+// earlier versions of the provider to the current schema version.
+func resourceVinylDNSRecordSetMigrateState(v int, is *terraform.InstanceState, meta interface{}) (*terraform.InstanceState, error) {
+	switch v {
+	case 0:
+		log.Println("[INFO] Found vinyldns record set state v0; migrating to v1")
+		return migrateRecordSetStateV0toV1(is)
+	default:
+		return is, fmt.Errorf("unexpected vinyldns record set schema version: %d", v)
+	}
+}
+
+// migrateRecordSetStateV0toV1 copies a TXT record set's record_text into the
+// record_texts set. record_text is kept, so configuration still using it plans
+// no change, and configuration moved to record_texts finds its value there.
+func migrateRecordSetStateV0toV1(is *terraform.InstanceState) (*terraform.InstanceState, error) {
+	if is.Empty() || is.Attributes == nil {
+		log.Println("[DEBUG] empty vinyldns record set state; nothing to migrate")
+		return is, nil
+	}
+
+	text := is.Attributes["record_text"]
+	if text == "" {
+		return is, nil
+	}
+
+	log.Printf("[DEBUG] vinyldns record set attributes before migration: %#v", is.Attributes)
+
+	is.Attributes["record_texts.#"] = "1"
+	is.Attributes["record_texts."+strconv.Itoa(hashcode.String(text))] = text
+
+	log.Printf("[DEBUG] vinyldns record set attributes after migration: %#v", is.Attributes)
+
+	return is, nil
+}
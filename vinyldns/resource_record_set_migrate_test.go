@@ -0,0 +1,78 @@
+/*
+Copyright 2018 Comcast Cable Communications Management, LLC
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vinyldns
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestResourceVinylDNSRecordSetMigrateStateV0toV1(t *testing.T) {
+	text := "Lorem ipsum and all that jazz"
+	is := &terraform.InstanceState{
+		ID: "record-set-id",
+		Attributes: map[string]string{
+			"name":        "txt-terraformtestrecordset",
+			"type":        "TXT",
+			"record_text": text,
+		},
+	}
+
+	is, err := resourceVinylDNSRecordSetMigrateState(0, is, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := map[string]string{
+		"name":                   "txt-terraformtestrecordset",
+		"type":                   "TXT",
+		"record_text":            text,
+		"record_texts.#":         "1",
+		"record_texts.582282085": text,
+	}
+	if len(is.Attributes) != len(expected) {
+		t.Errorf("expected attributes %#v; got %#v", expected, is.Attributes)
+	}
+	for k, v := range expected {
+		if is.Attributes[k] != v {
+			t.Errorf("expected %s to be %q; got %q", k, v, is.Attributes[k])
+		}
+	}
+}
+
+func TestResourceVinylDNSRecordSetMigrateStateV0toV1NoText(t *testing.T) {
+	is := &terraform.InstanceState{
+		ID: "record-set-id",
+		Attributes: map[string]string{
+			"name":               "terraformtestrecordset",
+			"type":               "A",
+			"record_addresses.#": "1",
+		},
+	}
+
+	is, err := resourceVinylDNSRecordSetMigrateState(0, is, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if _, ok := is.Attributes["record_texts.#"]; ok || len(is.Attributes) != 3 {
+		t.Errorf("expected non-TXT state to be left as is; got %#v", is.Attributes)
+	}
+}
+
+func TestResourceVinylDNSRecordSetMigrateStateEmpty(t *testing.T) {
+	if _, err := resourceVinylDNSRecordSetMigrateState(0, &terraform.InstanceState{}, nil); err != nil {
+		t.Fatalf("unexpected error migrating empty state: %s", err)
+	}
+}
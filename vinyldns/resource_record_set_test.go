@@ -13,14 +13,60 @@ limitations under the License.
 package vinyldns
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/hashicorp/terraform/terraform"
 	"github.com/vinyldns/go-vinyldns/vinyldns"
 )
 
+func init() {
+	resource.AddTestSweepers("vinyldns_record_set", &resource.Sweeper{
+		Name: "vinyldns_record_set",
+		F:    testSweepVinylDNSRecordSets,
+	})
+}
+
+func testSweepVinylDNSRecordSets(region string) error {
+	client := sweeperClient()
+	zones, err := client.ZonesListAll(vinyldns.ListFilter{})
+	if err != nil {
+		return fmt.Errorf("Error listing zones to sweep record sets: %s", err)
+	}
+
+	for _, z := range zones {
+		rss, err := client.RecordSetsListAll(z.ID, vinyldns.ListFilter{})
+		if err != nil {
+			return fmt.Errorf("Error listing record sets in zone %s: %s", z.ID, err)
+		}
+
+		for _, rs := range rss {
+			if !strings.Contains(rs.Name, "terraformtestrecordset") {
+				continue
+			}
+
+			log.Printf("[INFO] sweeping record set %s in zone %s", rs.Name, z.Name)
+			if _, err := client.RecordSetDelete(z.ID, rs.ID); err != nil {
+				return fmt.Errorf("Error sweeping record set %s: %s", rs.ID, err)
+			}
+		}
+	}
+
+	return nil
+}
+
 func TestAccVinylDNSRecordSetBasic(t *testing.T) {
 	resource.Test(t, resource.TestCase{
 		PreCheck:     func() { testAccPreCheck(t) },
@@ -34,6 +80,9 @@ func TestAccVinylDNSRecordSetBasic(t *testing.T) {
 					testAccCheckVinylDNSRecordSetExists("vinyldns_record_set.test_cname_record_set"),
 					testAccCheckVinylDNSRecordSetExists("vinyldns_record_set.test_txt_record_set"),
 					resource.TestCheckResourceAttr("vinyldns_record_set.test_a_record_set", "name", "terraformtestrecordset"),
+					resource.TestCheckResourceAttr("vinyldns_record_set.test_a_record_set", "tags.owner", "dns-team"),
+					resource.TestCheckResourceAttr("vinyldns_record_set.test_a_record_set", "zone_name", "system-test."),
+					resource.TestCheckResourceAttr("vinyldns_record_set.test_a_record_set", "ttl", "6000"),
 					resource.TestCheckResourceAttr("vinyldns_record_set.test_cname_record_set", "name", "cname-terraformtestrecordset"),
 					resource.TestCheckResourceAttr("vinyldns_record_set.test_txt_record_set", "name", "txt-terraformtestrecordset"),
 				),
@@ -42,8 +91,1528 @@ func TestAccVinylDNSRecordSetBasic(t *testing.T) {
 	})
 }
 
+func TestAccVinylDNSRecordSetRename(t *testing.T) {
+	var originalID string
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccVinylDNSRecordSetDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: fmt.Sprintf(testAccVinylDNSRecordSetConfigRename, "terraformtestrecordset"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckVinylDNSRecordSetExists("vinyldns_record_set.test_rename_record_set"),
+					testAccStoreVinylDNSRecordSetID("vinyldns_record_set.test_rename_record_set", &originalID),
+				),
+			},
+			resource.TestStep{
+				Config: fmt.Sprintf(testAccVinylDNSRecordSetConfigRename, "renamed-terraformtestrecordset"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckVinylDNSRecordSetExists("vinyldns_record_set.test_rename_record_set"),
+					resource.TestCheckResourceAttr("vinyldns_record_set.test_rename_record_set", "name", "renamed-terraformtestrecordset"),
+					testAccCheckVinylDNSRecordSetRecreated("vinyldns_record_set.test_rename_record_set", &originalID),
+				),
+			},
+		},
+	})
+}
+
+// vinyldns can't move a record set between zones, so a new zone_id replaces it
+func TestAccVinylDNSRecordSetMoveZone(t *testing.T) {
+	var originalID string
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccVinylDNSRecordSetDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: fmt.Sprintf(testAccVinylDNSRecordSetConfigMoveZone, "test_zone"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckVinylDNSRecordSetExists("vinyldns_record_set.test_move_record_set"),
+					resource.TestCheckResourceAttr("vinyldns_record_set.test_move_record_set", "zone_name", "system-test."),
+					testAccStoreVinylDNSRecordSetID("vinyldns_record_set.test_move_record_set", &originalID),
+				),
+			},
+			resource.TestStep{
+				Config: fmt.Sprintf(testAccVinylDNSRecordSetConfigMoveZone, "other_zone"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckVinylDNSRecordSetExists("vinyldns_record_set.test_move_record_set"),
+					resource.TestCheckResourceAttr("vinyldns_record_set.test_move_record_set", "zone_name", "ok."),
+					testAccCheckVinylDNSRecordSetRecreated("vinyldns_record_set.test_move_record_set", &originalID),
+				),
+			},
+		},
+	})
+}
+
+func TestRecordSetZoneIDForcesNew(t *testing.T) {
+	if !resourceVinylDNSRecordSet().Schema["zone_id"].ForceNew {
+		t.Error("expected a new zone_id to replace the record set, as vinyldns can't move it between zones")
+	}
+}
+
+func TestRecordSetZoneIDChangePlansReplacement(t *testing.T) {
+	meta, closeServer := testMeta(func(w http.ResponseWriter, r *http.Request) {
+		http.NotFound(w, r)
+	})
+	defer closeServer()
+
+	state := map[string]string{"name": "txt", "type": "TXT", "zone_id": "old-zone-id"}
+	diff := testResourceDiff(t, resourceVinylDNSRecordSet(), state, map[string]interface{}{"name": "txt", "type": "TXT", "zone_id": "new-zone-id"}, meta)
+
+	if a := diff.Attributes["zone_id"]; a == nil || a.New != "new-zone-id" || !a.RequiresNew || !diff.RequiresNew() {
+		t.Errorf("expected a new zone_id to plan the record set's replacement; got %#v", a)
+	}
+}
+
+func testAccStoreVinylDNSRecordSetID(n string, id *string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found %s", n)
+		}
+
+		*id = rs.Primary.ID
+
+		return nil
+	}
+}
+
+func testAccCheckVinylDNSRecordSetRecreated(n string, originalID *string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found %s", n)
+		}
+
+		if rs.Primary.ID == *originalID {
+			return fmt.Errorf("RecordSet %s was updated in place rather than recreated", rs.Primary.ID)
+		}
+
+		return nil
+	}
+}
+
+func TestAccVinylDNSRecordSetWildcard(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccVinylDNSRecordSetDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccVinylDNSRecordSetConfigWildcard,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckVinylDNSRecordSetExists("vinyldns_record_set.test_wildcard_record_set"),
+					resource.TestCheckResourceAttr("vinyldns_record_set.test_wildcard_record_set", "name", "*"),
+				),
+			},
+			resource.TestStep{
+				Config:   testAccVinylDNSRecordSetConfigWildcard,
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+// importing takes only the zone and record set IDs, so verifying the import
+// shows type and records alike are read back from vinyldns
+func TestAccVinylDNSRecordSetImport(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccVinylDNSRecordSetDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccVinylDNSRecordSetConfigBasic,
+			},
+			resource.TestStep{
+				ResourceName:      "vinyldns_record_set.test_a_record_set",
+				ImportState:       true,
+				ImportStateVerify: true,
+				ImportStateIdFunc: testAccVinylDNSRecordSetImportID("vinyldns_record_set.test_a_record_set"),
+			},
+			resource.TestStep{
+				Config:   testAccVinylDNSRecordSetConfigBasic,
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+// records_json is read back from vinyldns like the record_* arguments, so
+// records changed outside of Terraform are planned back
+func TestAccVinylDNSRecordSetRecordsJSONDrift(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccVinylDNSRecordSetDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccVinylDNSRecordSetConfigRecordsJSON,
+				Check: testAccChangeVinylDNSRecordSetRecords("vinyldns_record_set.test_srv_record_set", []vinyldns.Record{
+					{Priority: 10, Weight: 5, Port: 5061, Target: "sip.system-test."},
+				}),
+			},
+			resource.TestStep{
+				Config:             testAccVinylDNSRecordSetConfigRecordsJSON,
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+// testAccLongTXT is too long for one TXT character-string; it's sent to
+// vinyldns as it is, and vinyldns splits it into several
+var testAccLongTXT = "v=DKIM1; k=rsa; p=" + strings.Repeat("MIIBIjANBgkqhkiG9w0BAQEFAAOCAQ8A", 10)
+
+// the text the test resolver serves must be the text configured, joined
+// back together from the strings vinyldns split it into
+func TestAccVinylDNSRecordSetLongTXT(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccVinylDNSRecordSetDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: fmt.Sprintf(testAccVinylDNSRecordSetConfigLongTXT, testAccLongTXT),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("vinyldns_record_set.test_long_txt_record_set", "record_texts.#", "1"),
+					testAccCheckVinylDNSRecordSetResolvesTXT("vinyldns_record_set.test_long_txt_record_set", testAccLongTXT),
+				),
+			},
+			resource.TestStep{
+				Config:   fmt.Sprintf(testAccVinylDNSRecordSetConfigLongTXT, testAccLongTXT),
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+// testAccResolver is the DNS server of the vinyldns test backend,
+// overridden with VINYLDNS_TEST_RESOLVER.
+func testAccResolver() string {
+	if addr := os.Getenv("VINYLDNS_TEST_RESOLVER"); addr != "" {
+		return addr
+	}
+
+	return "127.0.0.1:19001"
+}
+
+// testAccCheckVinylDNSRecordSetResolvesTXT checks the test resolver serves
+// exactly text for the TXT record set.
+func testAccCheckVinylDNSRecordSetResolvesTXT(n, text string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		fqdn := normalizeName(rs.Primary.Attributes["name"] + "." + rs.Primary.Attributes["zone_name"])
+		resolver := propagationResolver(testAccResolver())
+
+		return waitForPropagation(fqdn+" at resolver "+testAccResolver(), []string{text}, time.Minute, func() ([]string, error) {
+			ctx, cancel := context.WithTimeout(context.Background(), propagationLookupTimeout)
+			defer cancel()
+
+			return lookupRecords(ctx, resolver, "TXT", fqdn)
+		})
+	}
+}
+
+// testAccChangeVinylDNSRecordSetRecords replaces the record set's records
+// outside of Terraform.
+func testAccChangeVinylDNSRecordSetRecords(n string, records []vinyldns.Record) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		meta := testAccProvider.Meta()
+		client := meta.(*Config).Client
+		readRs, err := client.RecordSet(rs.Primary.Attributes["zone_id"], rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+
+		readRs.Records = records
+		change, err := client.RecordSetUpdate(&readRs)
+		if err != nil {
+			return err
+		}
+
+		return waitUntilRecordSetChangeDeployed(meta, readRs.ZoneID, readRs.ID, change.ChangeID)
+	}
+}
+
+func testAccVinylDNSRecordSetImportID(n string) resource.ImportStateIdFunc {
+	return func(s *terraform.State) (string, error) {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return "", fmt.Errorf("Not found: %s", n)
+		}
+
+		return rs.Primary.Attributes["zone_id"] + ":" + rs.Primary.ID, nil
+	}
+}
+
+func TestParseRecordSetImportID(t *testing.T) {
+	zoneID, recordSetID, err := parseRecordSetImportID("zone-id:record-set-id")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if zoneID != "zone-id" || recordSetID != "record-set-id" {
+		t.Errorf("expected zone-id and record-set-id; got %s and %s", zoneID, recordSetID)
+	}
+
+	for _, id := range []string{"", "record-set-id", ":record-set-id", "zone-id:"} {
+		if _, _, err := parseRecordSetImportID(id); err == nil {
+			t.Errorf("expected an error for import ID %q", id)
+		}
+	}
+}
+
+func TestRecordSetZoneID(t *testing.T) {
+	zoneID, err := defaultedZoneID("zone-id", &Config{DefaultZoneID: "default-zone-id"})
+	if err != nil || zoneID != "zone-id" {
+		t.Errorf("expected the record set's own zone-id; got %q, %v", zoneID, err)
+	}
+
+	zoneID, err = defaultedZoneID("", &Config{DefaultZoneID: "default-zone-id"})
+	if err != nil || zoneID != "default-zone-id" {
+		t.Errorf("expected the provider's default-zone-id; got %q, %v", zoneID, err)
+	}
+
+	if _, err := defaultedZoneID("", &Config{}); err == nil {
+		t.Error("expected an error without a zone_id or default_zone_id")
+	}
+
+	for _, r := range []*schema.Resource{resourceVinylDNSRecordSet(), resourceVinylDNSRecordSets()} {
+		if s := r.Schema["zone_id"]; s.Required || !s.Optional || !s.Computed || !s.ForceNew {
+			t.Error("expected zone_id to be optional, defaulting to default_zone_id, and to replace record sets it moves")
+		}
+		if r.CustomizeDiff == nil || !r.Schema["zone_id_from_default"].Computed {
+			t.Error("expected default_zone_id to be applied during plan")
+		}
+	}
+}
+
+func TestPlanDefaultZoneID(t *testing.T) {
+	meta, closeServer := testMeta(func(w http.ResponseWriter, r *http.Request) {
+		http.NotFound(w, r)
+	})
+	defer closeServer()
+	meta.DefaultZoneID = "default-zone-id"
+
+	raw := map[string]interface{}{"name": "txt", "type": "TXT"}
+	state := func(zoneID, fromDefault string) map[string]string {
+		return map[string]string{"name": "txt", "type": "TXT", "zone_id": zoneID, "zone_id_from_default": fromDefault}
+	}
+
+	diff := testResourceDiff(t, resourceVinylDNSRecordSet(), nil, raw, meta)
+	if a := diff.Attributes["zone_id"]; a == nil || a.New != "default-zone-id" || a.NewComputed {
+		t.Errorf("expected a new record set to be planned in the default zone; got %#v", a)
+	}
+	if a := diff.Attributes["zone_id_from_default"]; a == nil || a.New != "true" {
+		t.Errorf("expected a new record set to be marked as taking the default zone; got %#v", a)
+	}
+
+	diff = testResourceDiff(t, resourceVinylDNSRecordSet(), state("old-default-zone-id", "true"), raw, meta)
+	if a := diff.Attributes["zone_id"]; a == nil || a.New != "default-zone-id" || !diff.RequiresNew() {
+		t.Errorf("expected a new default zone to replace the record set; got %#v", a)
+	}
+
+	diff = testResourceDiff(t, resourceVinylDNSRecordSet(), state("own-zone-id", "false"), raw, meta)
+	if a := diff.Attributes["zone_id"]; a != nil || diff.RequiresNew() {
+		t.Errorf("expected a record set with its own zone to stay in it; got %#v", a)
+	}
+
+	configured := map[string]interface{}{"name": "txt", "type": "TXT", "zone_id": "own-zone-id"}
+	diff = testResourceDiff(t, resourceVinylDNSRecordSet(), state("default-zone-id", "true"), configured, meta)
+	if a := diff.Attributes["zone_id"]; a == nil || a.New != "own-zone-id" || !diff.RequiresNew() {
+		t.Errorf("expected a configured zone_id to take over from the default; got %#v", a)
+	}
+	if a := diff.Attributes["zone_id_from_default"]; a == nil || a.New != "false" {
+		t.Errorf("expected a configured zone_id to clear zone_id_from_default; got %#v", a)
+	}
+
+	diff = testResourceDiff(t, resourceVinylDNSRecordSets(), nil, map[string]interface{}{}, meta)
+	if a := diff.Attributes["zone_id"]; a == nil || a.New != "default-zone-id" {
+		t.Errorf("expected vinyldns_record_sets to be planned in the default zone; got %#v", a)
+	}
+}
+
+func TestImportedRecordsJSON(t *testing.T) {
+	recordsJSON, err := importedRecordsJSON(vinyldns.RecordSet{Type: "A", Records: []vinyldns.Record{{Address: "127.0.0.1"}}})
+	if err != nil || recordsJSON != "" {
+		t.Errorf("expected no records_json for an A record set; got %q, %v", recordsJSON, err)
+	}
+
+	srv := []vinyldns.Record{{Priority: 1, Weight: 5, Port: 443, Target: "svc.example.com."}}
+	recordsJSON, err = importedRecordsJSON(vinyldns.RecordSet{Type: "SRV", Records: srv})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	records, err := parseRecordsJSON(recordsJSON)
+	if err != nil {
+		t.Fatalf("expected records_json to be valid; got %s", err)
+	}
+	if !reflect.DeepEqual(records, srv) {
+		t.Errorf("expected %#v; got %#v", srv, records)
+	}
+}
+
+func TestSuppressEquivalentRecordsJSON(t *testing.T) {
+	cases := []struct {
+		old      string
+		new      string
+		expected bool
+	}{
+		{`[{"priority":1,"weight":5,"port":443,"target":"svc.example.com."}]`, `[
+			{"target": "svc.example.com.", "port": 443, "weight": 5, "priority": 1}
+		]`, true},
+		{`[{"priority":1,"weight":5,"port":443,"target":"svc.example.com."}]`, `[{"priority":2,"weight":5,"port":443,"target":"svc.example.com."}]`, false},
+		{`[{"address":"127.0.0.1"},{"address":"127.0.0.2"}]`, `[{"address":"127.0.0.2"},{"address":"127.0.0.1"}]`, true},
+		{`[{"address":"127.0.0.1"},{"address":"127.0.0.1"}]`, `[{"address":"127.0.0.1"}]`, false},
+		{"", `[{"address":"127.0.0.1"}]`, false},
+		{`[{"address":"127.0.0.1"}]`, "not json", false},
+	}
+
+	for _, c := range cases {
+		if got := suppressEquivalentRecordsJSON("records_json", c.old, c.new, nil); got != c.expected {
+			t.Errorf("expected %t for %s and %s; got %t", c.expected, c.old, c.new, got)
+		}
+	}
+}
+
+func TestReverseZoneRecordName(t *testing.T) {
+	cases := []struct {
+		zone     string
+		host     string
+		expected string
+	}{
+		{"2.0.192.in-addr.arpa.", "42", "42"},
+		{"2.0.192.in-addr.arpa.", "0", "0"},
+		{"168.192.in-addr.arpa.", "1.42", "42.1"},
+	}
+
+	for _, c := range cases {
+		name, err := reverseZoneRecordName(c.zone, c.host)
+		if err != nil {
+			t.Errorf("unexpected error for host %s in zone %s: %s", c.host, c.zone, err)
+		}
+		if name != c.expected {
+			t.Errorf("expected host %s in zone %s to be named %s; got %s", c.host, c.zone, c.expected, name)
+		}
+	}
+
+	if _, err := reverseZoneRecordName("2.0.192.in-addr.arpa.", "1.42"); err == nil {
+		t.Error("expected an error for a host with too many octets for a /24 reverse zone")
+	}
+
+	if _, err := reverseZoneRecordName("system-test.", "42"); err == nil {
+		t.Error("expected an error for a host in a forward zone")
+	}
+}
+
+func TestValidateHostOctets(t *testing.T) {
+	for _, v := range []string{"0", "42", "255", "1.42"} {
+		if _, errs := validateHostOctets(v, "host"); len(errs) != 0 {
+			t.Errorf("expected %s to be valid; got %v", v, errs)
+		}
+	}
+
+	for _, v := range []string{"", "256", "-1", "042", "a", "1..2"} {
+		if _, errs := validateHostOctets(v, "host"); len(errs) == 0 {
+			t.Errorf("expected %q to be invalid", v)
+		}
+	}
+}
+
+func TestRecordsRejectsSOA(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceVinylDNSRecordSet().Schema, map[string]interface{}{
+		"name":    "system-test.",
+		"zone_id": "123",
+		"type":    "SOA",
+		"ttl":     6000,
+	})
+
+	if _, err := records(d, &Config{}); err != ErrUnsupportedType {
+		t.Errorf("expected ErrUnsupportedType for an SOA record set; got %v", err)
+	}
+}
+
+func TestRecordsCNAMEAutoTrailingDot(t *testing.T) {
+	raw := map[string]interface{}{
+		"name":         "cname-terraformtestrecordset",
+		"zone_id":      "123",
+		"type":         "CNAME",
+		"record_cname": "foo-bar.com",
+	}
+
+	if _, err := records(schema.TestResourceDataRaw(t, resourceVinylDNSRecordSet().Schema, raw), &Config{}); err != ErrTrailingDotRequired {
+		t.Errorf("expected ErrTrailingDotRequired without auto-append; got %v", err)
+	}
+
+	rs, err := records(schema.TestResourceDataRaw(t, resourceVinylDNSRecordSet().Schema, raw), &Config{CNAMEAutoTrailingDot: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(rs) != 1 || rs[0].CName != "foo-bar.com." {
+		t.Errorf("expected the trailing dot to be appended; got %#v", rs)
+	}
+}
+
+func TestRecordsNSPTRAutoTrailingDot(t *testing.T) {
+	ns := map[string]interface{}{
+		"zone_id":         "123",
+		"type":            "NS",
+		"record_nsdnames": []interface{}{"ns1.example.com"},
+	}
+
+	if _, err := records(schema.TestResourceDataRaw(t, resourceVinylDNSRecordSet().Schema, ns), &Config{}); err != ErrTrailingDotRequired {
+		t.Errorf("expected ErrTrailingDotRequired without auto-append; got %v", err)
+	}
+
+	rs, err := records(schema.TestResourceDataRaw(t, resourceVinylDNSRecordSet().Schema, ns), &Config{NSPTRAutoTrailingDot: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(rs) != 1 || rs[0].NSDName != "ns1.example.com." {
+		t.Errorf("expected the trailing dot to be appended; got %#v", rs)
+	}
+
+	ptr := map[string]interface{}{
+		"zone_id":          "123",
+		"type":             "PTR",
+		"record_ptrdnames": []interface{}{"host.example.com"},
+	}
+
+	rs, err = records(schema.TestResourceDataRaw(t, resourceVinylDNSRecordSet().Schema, ptr), &Config{NSPTRAutoTrailingDot: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(rs) != 1 || rs[0].PTRDName != "host.example.com." {
+		t.Errorf("expected the trailing dot to be appended; got %#v", rs)
+	}
+}
+
+func TestWithTrailingDots(t *testing.T) {
+	got := withTrailingDots([]string{"ns1.example.com", "ns2.example.com.", ""})
+	expected := []string{"ns1.example.com.", "ns2.example.com.", ""}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v; got %v", expected, got)
+	}
+}
+
+func TestHashName(t *testing.T) {
+	if hashName("ns1.example.com") != hashName("ns1.example.com.") {
+		t.Error("expected names differing only by the trailing dot to hash the same")
+	}
+
+	if hashName("ns1.example.com.") == hashName("ns2.example.com.") {
+		t.Error("expected different names to hash differently")
+	}
+
+	for _, k := range []string{"record_nsdnames", "record_ptrdnames"} {
+		if resourceVinylDNSRecordSet().Schema[k].Set == nil {
+			t.Errorf("expected %s to be hashed by hashName", k)
+		}
+	}
+}
+
+func TestRecordsDispatch(t *testing.T) {
+	cases := []struct {
+		name     string
+		raw      map[string]interface{}
+		expected []vinyldns.Record
+		err      bool
+	}{
+		{
+			name:     "A",
+			raw:      map[string]interface{}{"type": "A", "record_addresses": []interface{}{"127.0.0.1", "127.0.0.2"}},
+			expected: []vinyldns.Record{{Address: "127.0.0.1"}, {Address: "127.0.0.2"}},
+		},
+		{
+			name:     "AAAA",
+			raw:      map[string]interface{}{"type": "AAAA", "record_addresses": []interface{}{"[2001:DB8::1]"}},
+			expected: []vinyldns.Record{{Address: "2001:db8::1"}},
+		},
+		{
+			name:     "CNAME",
+			raw:      map[string]interface{}{"type": "CNAME", "record_cname": "foo-bar.com."},
+			expected: []vinyldns.Record{{CName: "foo-bar.com."}},
+		},
+		{
+			name: "MX",
+			raw: map[string]interface{}{"type": "MX", "record_mx": []interface{}{
+				map[string]interface{}{"preference": 20, "exchange": "mx2.example.com."},
+				map[string]interface{}{"preference": 10, "exchange": "mx1.example.com."},
+			}},
+			expected: []vinyldns.Record{{Preference: 10, Exchange: "mx1.example.com."}, {Preference: 20, Exchange: "mx2.example.com."}},
+		},
+		{
+			name:     "NS",
+			raw:      map[string]interface{}{"type": "NS", "record_nsdnames": []interface{}{"ns1.example.com."}},
+			expected: []vinyldns.Record{{NSDName: "ns1.example.com."}},
+		},
+		{
+			name:     "PTR",
+			raw:      map[string]interface{}{"type": "PTR", "record_ptrdnames": []interface{}{"host.example.com."}},
+			expected: []vinyldns.Record{{PTRDName: "host.example.com."}},
+		},
+		{
+			name:     "TXT record_texts",
+			raw:      map[string]interface{}{"type": "TXT", "record_texts": []interface{}{"one", "two"}},
+			expected: []vinyldns.Record{{Text: "one"}, {Text: "two"}},
+		},
+		{
+			name:     "TXT record_text",
+			raw:      map[string]interface{}{"type": "TXT", "record_text": "one"},
+			expected: []vinyldns.Record{{Text: "one"}},
+		},
+		{
+			name:     "SRV records_json",
+			raw:      map[string]interface{}{"type": "SRV", "records_json": `[{"priority": 10, "weight": 5, "port": 5060, "target": "sip.example.com."}]`},
+			expected: []vinyldns.Record{{Priority: 10, Weight: 5, Port: 5060, Target: "sip.example.com."}},
+		},
+		{
+			name: "SOA",
+			raw:  map[string]interface{}{"type": "SOA"},
+			err:  true,
+		},
+		{
+			name: "empty CNAME",
+			raw:  map[string]interface{}{"type": "CNAME"},
+			err:  true,
+		},
+		{
+			name: "CNAME without trailing dot",
+			raw:  map[string]interface{}{"type": "CNAME", "record_cname": "foo-bar.com"},
+			err:  true,
+		},
+		{
+			name: "NS without trailing dot",
+			raw:  map[string]interface{}{"type": "NS", "record_nsdnames": []interface{}{"ns1.example.com"}},
+			err:  true,
+		},
+		{
+			name: "A without addresses",
+			raw:  map[string]interface{}{"type": "A"},
+			err:  true,
+		},
+		{
+			name: "DS without records_json",
+			raw:  map[string]interface{}{"type": "DS"},
+			err:  true,
+		},
+	}
+
+	for _, c := range cases {
+		c.raw["name"] = "terraformtestrecordset"
+		c.raw["zone_id"] = "123"
+		d := schema.TestResourceDataRaw(t, resourceVinylDNSRecordSet().Schema, c.raw)
+
+		rs, err := records(d, &Config{})
+		if c.err {
+			if err == nil {
+				t.Errorf("%s: expected an error; got %#v", c.name, rs)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", c.name, err)
+			continue
+		}
+
+		if !recordsMatch(rs, c.expected) {
+			t.Errorf("%s: expected %#v; got %#v", c.name, c.expected, rs)
+		}
+	}
+}
+
+func TestSuppressTrailingDotDiff(t *testing.T) {
+	cases := []struct {
+		old, new string
+		suppress bool
+	}{
+		{"foo-bar.com.", "foo-bar.com", true},
+		{"foo-bar.com.", "foo-bar.com.", true},
+		{"foo-bar.com.", "baz.com", false},
+		{"", "foo-bar.com", false},
+	}
+
+	for _, c := range cases {
+		if suppress := suppressTrailingDotDiff("record_cname", c.old, c.new, nil); suppress != c.suppress {
+			t.Errorf("expected suppressing %q -> %q to be %t", c.old, c.new, c.suppress)
+		}
+	}
+}
+
+func TestTTLSeconds(t *testing.T) {
+	cases := map[string]int{
+		"1s":    1,
+		"5m":    300,
+		"1h":    3600,
+		"1h30m": 5400,
+	}
+
+	for v, expected := range cases {
+		ttl, err := ttlSeconds(v)
+		if err != nil {
+			t.Errorf("unexpected error for %q: %s", v, err)
+		}
+		if ttl != expected {
+			t.Errorf("expected %q to be %d seconds; got %d", v, expected, ttl)
+		}
+	}
+
+	for _, v := range []string{"", "300", "soon", "0s", "-5m", "1500ms"} {
+		if _, es := validateTTLDuration(v, "ttl_duration"); len(es) == 0 {
+			t.Errorf("expected %q to be invalid", v)
+		}
+	}
+}
+
+func TestRequireAddresses(t *testing.T) {
+	cases := []struct {
+		recordType string
+		count      int
+		expectErr  bool
+	}{
+		{"A", 0, true},
+		{"AAAA", 0, true},
+		{"A", 1, false},
+		{"AAAA", 2, false},
+		{"CNAME", 0, false},
+	}
+
+	for _, c := range cases {
+		err := requireAddresses(c.recordType, c.count)
+		if c.expectErr && err == nil {
+			t.Errorf("expected an error for %s record set with %d addresses", c.recordType, c.count)
+		}
+		if !c.expectErr && err != nil {
+			t.Errorf("unexpected error for %s record set with %d addresses: %s", c.recordType, c.count, err)
+		}
+	}
+}
+
+func TestRejectApexNS(t *testing.T) {
+	cases := []struct {
+		name      string
+		expectErr bool
+	}{
+		{"example.com.", true},
+		{"example.com", true},
+		{"EXAMPLE.com.", true},
+		{"@", true},
+		{"sub", false},
+		{"sub.example.com.", false},
+	}
+
+	for _, c := range cases {
+		err := rejectApexNS(c.name, "example.com.")
+		if c.expectErr && err == nil {
+			t.Errorf("expected an error for NS record set %s", c.name)
+		}
+		if !c.expectErr && err != nil {
+			t.Errorf("unexpected error for NS record set %s: %s", c.name, err)
+		}
+	}
+}
+
+func TestRejectCreatedByGroup(t *testing.T) {
+	cases := []struct {
+		group     string
+		shared    bool
+		expectErr bool
+	}{
+		{"", false, false},
+		{"", true, false},
+		{"group-id", true, false},
+		{"group-id", false, true},
+	}
+
+	for _, c := range cases {
+		err := rejectCreatedByGroup(c.group, vinyldns.Zone{Name: "example.com.", Shared: c.shared})
+		if c.expectErr && err == nil {
+			t.Errorf("expected an error for created_by_group %q in a zone with shared %t", c.group, c.shared)
+		}
+		if !c.expectErr && err != nil {
+			t.Errorf("unexpected error for created_by_group %q in a zone with shared %t: %s", c.group, c.shared, err)
+		}
+	}
+}
+
+func TestCheckRecordCount(t *testing.T) {
+	cases := []struct {
+		count     int
+		max       int
+		expectErr bool
+	}{
+		{1, 1000, false},
+		{1000, 1000, false},
+		{1001, 1000, true},
+		{65536, 0, false},
+	}
+
+	for _, c := range cases {
+		err := checkRecordCount(c.count, c.max)
+		if c.expectErr && err == nil {
+			t.Errorf("expected an error for %d records with a max of %d", c.count, c.max)
+		}
+		if !c.expectErr && err != nil {
+			t.Errorf("unexpected error for %d records with a max of %d: %s", c.count, c.max, err)
+		}
+	}
+}
+
+func TestMissingCNAMETarget(t *testing.T) {
+	meta, closeServer := testMeta(func(w http.ResponseWriter, r *http.Request) {
+		// vinyldns filters record sets by prefix, so near matches come back too
+		w.Write([]byte(`{"recordSets":[{"id":"rs-id","name":"web-1","type":"A"},{"id":"rs-id-2","name":"web","type":"A"}]}`))
+	})
+	defer closeServer()
+
+	z := vinyldns.Zone{ID: "zone-id", Name: "example.com."}
+
+	cases := []struct {
+		target   string
+		relative string
+		missing  bool
+	}{
+		{"web.example.com.", "web", false},
+		{"web-1.example.com.", "web-1", false},
+		{"we.example.com.", "we", true},
+		{"app.other.com.", "", false},
+		{"example.com.", "", false},
+	}
+
+	for _, c := range cases {
+		relative, missing, err := missingCNAMETarget(meta, z, c.target)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if relative != c.relative || missing != c.missing {
+			t.Errorf("missingCNAMETarget(%q) = %q, %t; expected %q, %t", c.target, relative, missing, c.relative, c.missing)
+		}
+	}
+}
+
+func TestInZoneNameServers(t *testing.T) {
+	names := inZoneNameServers([]string{
+		"ns1.sub.example.com.",
+		"NS2.Sub.Example.com.",
+		"ns3.example.net.",
+		"ns4.notexample.com.",
+		"ns5.example.com",
+	}, "example.com.")
+
+	if strings.Join(names, ",") != "ns1.sub,ns2.sub,ns5" {
+		t.Errorf("expected only the name servers within the zone, relative to it; got %v", names)
+	}
+}
+
+func TestParseRecordsJSON(t *testing.T) {
+	records, err := parseRecordsJSON(`[{"preference": 10, "exchange": "mail.example.com."}, {"text": "hi"}]`)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := []vinyldns.Record{
+		vinyldns.Record{Preference: 10, Exchange: "mail.example.com."},
+		vinyldns.Record{Text: "hi"},
+	}
+	if !recordsMatch(records, expected) {
+		t.Errorf("expected %#v; got %#v", expected, records)
+	}
+
+	for _, v := range []string{"", "{}", "[]", `[{"adress": "127.0.0.1"}]`, "not json"} {
+		if _, err := parseRecordsJSON(v); err == nil {
+			t.Errorf("expected an error for %q", v)
+		}
+	}
+}
+
+func TestFlattenRecords(t *testing.T) {
+	records := flattenRecords([]vinyldns.Record{
+		{Preference: 10, Exchange: "mx.example.com."},
+		{Priority: 1, Weight: 5, Port: 443, Target: "svc.example.com."},
+	})
+
+	if len(records) != 2 {
+		t.Fatalf("expected 2 records; got %d", len(records))
+	}
+
+	if records[0]["preference"] != 10 || records[0]["exchange"] != "mx.example.com." || records[0]["address"] != "" {
+		t.Errorf("unexpected MX record: %#v", records[0])
+	}
+
+	if records[1]["priority"] != 1 || records[1]["weight"] != 5 || records[1]["port"] != 443 || records[1]["target"] != "svc.example.com." {
+		t.Errorf("unexpected SRV record: %#v", records[1])
+	}
+
+	// every attribute of the schema is set, and nothing else
+	fields := recordResource().Schema
+	for _, r := range records {
+		if len(r) != len(fields) {
+			t.Errorf("expected %d fields; got %d", len(fields), len(r))
+		}
+		for k := range r {
+			if _, ok := fields[k]; !ok {
+				t.Errorf("field %s isn't in the records schema", k)
+			}
+		}
+	}
+
+	if got := flattenRecords(nil); got == nil || len(got) != 0 {
+		t.Errorf("expected no records; got %#v", got)
+	}
+}
+
+func TestSubmitRecordSetChangeRetriesFailedChanges(t *testing.T) {
+	submitted := []string{}
+	submit := func() (string, error) {
+		submitted = append(submitted, fmt.Sprintf("change-%d", len(submitted)+1))
+		return submitted[len(submitted)-1], nil
+	}
+
+	// the first change fails, the second completes
+	wait := func(changeID string) error {
+		if changeID == "change-1" {
+			return ErrRecordSetChangeFailed
+		}
+
+		return nil
+	}
+
+	if err := submitRecordSetChange(2, submit, wait); err != nil {
+		t.Fatalf("expected the resubmitted change to complete; got %s", err)
+	}
+	if len(submitted) != 2 {
+		t.Errorf("expected the change to be submitted twice; got %v", submitted)
+	}
+
+	submitted = []string{}
+	if err := submitRecordSetChange(0, submit, wait); err != ErrRecordSetChangeFailed {
+		t.Errorf("expected the failure without retries; got %v", err)
+	}
+	if len(submitted) != 1 {
+		t.Errorf("expected the change to be submitted once; got %v", submitted)
+	}
+
+	submitted = []string{}
+	alwaysFails := func(string) error { return ErrRecordSetChangeFailed }
+	if err := submitRecordSetChange(2, submit, alwaysFails); err != ErrRecordSetChangeFailed {
+		t.Errorf("expected the failure once retries ran out; got %v", err)
+	}
+	if len(submitted) != 3 {
+		t.Errorf("expected the change to be submitted three times; got %v", submitted)
+	}
+
+	submitted = []string{}
+	other := errors.New("boom")
+	if err := submitRecordSetChange(2, submit, func(string) error { return other }); err != other {
+		t.Errorf("expected other errors not to be retried; got %v", err)
+	}
+	if len(submitted) != 1 {
+		t.Errorf("expected the change to be submitted once; got %v", submitted)
+	}
+}
+
+func TestStringSetToStringSlice(t *testing.T) {
+	if got := stringSetToStringSlice(nil); got == nil || len(got) != 0 {
+		t.Errorf("expected an empty slice for a nil set; got %#v", got)
+	}
+
+	if got := stringSetToStringSlice(schema.NewSet(schema.HashString, nil)); got == nil || len(got) != 0 {
+		t.Errorf("expected an empty slice for an empty set; got %#v", got)
+	}
+
+	got := stringSetToStringSlice(schema.NewSet(schema.HashString, []interface{}{"ns2.example.com.", "ns1.example.com."}))
+	sort.Strings(got)
+	if len(got) != 2 || got[0] != "ns1.example.com." || got[1] != "ns2.example.com." {
+		t.Errorf("expected both values of the set; got %#v", got)
+	}
+}
+
+func TestRejectApexCNAME(t *testing.T) {
+	for _, name := range []string{"@", "example.com", "example.com.", "Example.COM."} {
+		err := rejectApexCNAME(name, "example.com.")
+		if err == nil {
+			t.Errorf("expected an error for apex CNAME record set %s", name)
+		} else if !strings.Contains(err.Error(), "A and AAAA record sets") {
+			t.Errorf("expected the error to say what to use instead; got %s", err)
+		}
+	}
+
+	for _, name := range []string{"www", "www.example.com", "example"} {
+		if err := rejectApexCNAME(name, "example.com."); err != nil {
+			t.Errorf("unexpected error for CNAME record set %s: %s", name, err)
+		}
+	}
+}
+
+func TestRedundantZoneSuffix(t *testing.T) {
+	cases := []struct {
+		name     string
+		zone     string
+		relative string
+		ok       bool
+	}{
+		{"www.example.com", "example.com.", "www", true},
+		{"www.example.com.", "example.com.", "www", true},
+		{"a.b.Example.COM", "example.com", "a.b", true},
+		{"www", "example.com.", "", false},
+		{"www.dev", "example.com.", "", false},
+		{"example.com", "example.com.", "", false},
+		{"wwwexample.com", "example.com.", "", false},
+	}
+
+	for _, c := range cases {
+		relative, ok := redundantZoneSuffix(c.name, c.zone)
+		if ok != c.ok || relative != c.relative {
+			t.Errorf("redundantZoneSuffix(%q, %q) = %q, %t; expected %q, %t", c.name, c.zone, relative, ok, c.relative, c.ok)
+		}
+	}
+}
+
+func TestRecordSetUpdateNoop(t *testing.T) {
+	requests := 0
+	meta, closeFn := testMeta(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	defer closeFn()
+
+	d := resourceVinylDNSRecordSet().Data(&terraform.InstanceState{
+		ID: "record-set-id",
+		Attributes: map[string]string{
+			"zone_id":                    "zone-id",
+			"name":                       "www",
+			"type":                       "A",
+			"ttl":                        "300",
+			"record_addresses.#":         "1",
+			"record_addresses.561618613": "127.0.0.1",
+		},
+	})
+
+	if err := resourceVinylDNSRecordSetUpdate(d, meta); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if requests != 0 {
+		t.Errorf("expected no requests to vinyldns for a no-op update; got %d", requests)
+	}
+}
+
+func TestRecordSetUpdatePreservesUnmanagedFields(t *testing.T) {
+	var submitted vinyldns.RecordSet
+	meta, closeFn := testMeta(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/zones/zone-id/recordsets/record-set-id" && r.Method == "PUT":
+			if err := json.NewDecoder(r.Body).Decode(&submitted); err != nil {
+				t.Errorf("unexpected error decoding the update: %s", err)
+			}
+			w.Write([]byte(`{"id":"change-id","status":"Pending"}`))
+		case r.URL.Path == "/zones/zone-id/recordsets/record-set-id":
+			w.Write([]byte(`{"recordSet":{"id":"record-set-id","zoneId":"zone-id","name":"www","type":"A","ttl":300,"account":"account-id","ownerGroupId":"owner-group-id","records":[{"address":"127.0.0.1"}]}}`))
+		case r.URL.Path == "/zones/zone-id/recordsets/record-set-id/changes/change-id":
+			w.Write([]byte(`{"id":"change-id","status":"Complete"}`))
+		case r.URL.Path == "/zones/zone-id":
+			w.Write([]byte(`{"zone":{"id":"zone-id","name":"example.com."}}`))
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	defer closeFn()
+
+	d := resourceVinylDNSRecordSet().Data(&terraform.InstanceState{
+		ID: "record-set-id",
+		Attributes: map[string]string{
+			"zone_id":                    "zone-id",
+			"name":                       "www",
+			"type":                       "A",
+			"ttl":                        "300",
+			"preserve_unmanaged_fields":  "true",
+			"record_addresses.#":         "1",
+			"record_addresses.561618613": "127.0.0.1",
+		},
+	})
+	d.Set("ttl", 600)
+
+	if err := resourceVinylDNSRecordSetUpdate(d, meta); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if submitted.TTL != 600 {
+		t.Errorf("expected the configured ttl to be submitted; got %d", submitted.TTL)
+	}
+	if submitted.Account != "account-id" || submitted.OwnerGroupID != "owner-group-id" {
+		t.Errorf("expected the fields vinyldns holds to survive the update; got %#v", submitted)
+	}
+}
+
+func TestRecordSetUpdateNeeded(t *testing.T) {
+	existing := vinyldns.RecordSet{
+		ID:      "record-set-id",
+		Name:    "www",
+		Type:    "A",
+		TTL:     300,
+		Status:  "Active",
+		Records: []vinyldns.Record{{Address: "127.0.0.1"}, {Address: "127.0.0.2"}},
+	}
+
+	noop := &vinyldns.RecordSet{
+		ID:      "record-set-id",
+		Name:    "www",
+		Type:    "A",
+		TTL:     300,
+		Records: []vinyldns.Record{{Address: "127.0.0.2"}, {Address: "127.0.0.1"}},
+	}
+	if recordSetUpdateNeeded(existing, noop) {
+		t.Error("expected no update for the same records in another order")
+	}
+
+	changes := []func(rs *vinyldns.RecordSet){
+		func(rs *vinyldns.RecordSet) { rs.TTL = 600 },
+		func(rs *vinyldns.RecordSet) { rs.OwnerGroupID = "group-id" },
+		func(rs *vinyldns.RecordSet) { rs.Records = rs.Records[:1] },
+		func(rs *vinyldns.RecordSet) {
+			rs.Records = []vinyldns.Record{{Address: "127.0.0.1"}, {Address: "127.0.0.3"}}
+		},
+	}
+	for i, change := range changes {
+		rs := *noop
+		change(&rs)
+		if !recordSetUpdateNeeded(existing, &rs) {
+			t.Errorf("expected change %d to need an update", i)
+		}
+	}
+}
+
+func TestCheckRecordSetVersion(t *testing.T) {
+	if err := checkRecordSetVersion("", "2018-10-01T12:00:00Z"); err != nil {
+		t.Errorf("unexpected error for state without an updated timestamp: %s", err)
+	}
+
+	if err := checkRecordSetVersion("2018-10-01T12:00:00Z", "2018-10-01T12:00:00Z"); err != nil {
+		t.Errorf("unexpected error for an unchanged record set: %s", err)
+	}
+
+	if err := checkRecordSetVersion("2018-10-01T12:00:00Z", "2018-10-02T08:30:00Z"); err == nil {
+		t.Error("expected an error for a record set updated since it was read")
+	}
+}
+
+func TestMXRecords(t *testing.T) {
+	mxs := []interface{}{
+		map[string]interface{}{"preference": 20, "exchange": "mail1.example.com."},
+		map[string]interface{}{"preference": 10, "exchange": "mail2.example.com."},
+		map[string]interface{}{"preference": 10, "exchange": "mail1.example.com."},
+	}
+
+	hashes := map[int]bool{}
+	for _, mx := range mxs {
+		hashes[mxRecordHash(mx)] = true
+	}
+	if len(hashes) != len(mxs) {
+		t.Errorf("expected %d distinct MX record hashes; got %d", len(mxs), len(hashes))
+	}
+
+	expected := []vinyldns.Record{
+		vinyldns.Record{Preference: 10, Exchange: "mail1.example.com."},
+		vinyldns.Record{Preference: 10, Exchange: "mail2.example.com."},
+		vinyldns.Record{Preference: 20, Exchange: "mail1.example.com."},
+	}
+	records := mxRecordSets(mxs)
+	if len(records) != len(expected) {
+		t.Fatalf("expected %d MX records; got %d", len(expected), len(records))
+	}
+	for i := range expected {
+		if records[i] != expected[i] {
+			t.Errorf("expected MX record %d to be %#v; got %#v", i, expected[i], records[i])
+		}
+	}
+}
+
+func TestRecordsDiff(t *testing.T) {
+	old := []vinyldns.Record{{Address: "127.0.0.1"}, {Address: "127.0.0.2"}, {Address: "127.0.0.3"}}
+	new := []vinyldns.Record{{Address: "127.0.0.3"}, {Address: "127.0.0.4"}, {Address: "127.0.0.1"}}
+
+	added, removed := recordsDiff(old, new)
+	if recordsString(added) != `[{"address":"127.0.0.4"}]` {
+		t.Errorf("expected 127.0.0.4 to be added; got %s", recordsString(added))
+	}
+	if recordsString(removed) != `[{"address":"127.0.0.2"}]` {
+		t.Errorf("expected 127.0.0.2 to be removed; got %s", recordsString(removed))
+	}
+
+	added, removed = recordsDiff(old, old)
+	if len(added) != 0 || len(removed) != 0 {
+		t.Errorf("expected no difference between identical records; got %v added and %v removed", added, removed)
+	}
+}
+
+func TestRecordSetAccessError(t *testing.T) {
+	cases := []struct {
+		err      error
+		contains string
+	}{
+		{
+			&vinyldns.Error{ResponseCode: http.StatusForbidden, ResponseBody: "User ok does not have access to update foo.ok."},
+			"no write access to zone zone-id",
+		},
+		{
+			&vinyldns.Error{ResponseCode: http.StatusUnprocessableEntity, ResponseBody: `Zone "ok." is a shared zone, so owner group ID must be specified for record "foo".`},
+			"set owner_group_id",
+		},
+		{
+			&vinyldns.Error{ResponseCode: http.StatusBadRequest, ResponseBody: "Invalid TTL"},
+			"Invalid TTL",
+		},
+	}
+
+	for _, c := range cases {
+		err := recordSetAccessError(c.err, "zone-id")
+		if err == nil || !strings.Contains(err.Error(), c.contains) {
+			t.Errorf("expected an error containing %q; got %v", c.contains, err)
+		}
+	}
+
+	// the no-access guidance mustn't be given for a missing owner group
+	err := recordSetAccessError(cases[1].err, "zone-id")
+	if strings.Contains(err.Error(), "no write access") {
+		t.Errorf("expected the missing owner group to be reported on its own; got %s", err)
+	}
+}
+
+func TestNormalizeAddress(t *testing.T) {
+	equivalents := []string{
+		"2001:db8::1",
+		"2001:DB8::1",
+		"2001:DB8:0:0:0:0:0:1",
+		"2001:0db8:0000:0000:0000:0000:0000:0001",
+		"[2001:db8::1]",
+	}
+
+	for _, address := range equivalents {
+		if got := normalizeAddress(address); got != "2001:db8::1" {
+			t.Errorf("expected %s to normalize to 2001:db8::1; got %s", address, got)
+		}
+	}
+
+	for _, address := range []string{"127.0.0.1", "[127.0.0.1]", "[not-an-address]"} {
+		if got := normalizeAddress(address); got != address {
+			t.Errorf("expected %s to pass through unchanged; got %s", address, got)
+		}
+	}
+}
+
+func TestAddressRecordSets(t *testing.T) {
+	a := addressRecordSets("A", []string{"[127.0.0.1]"})
+	if len(a) != 1 || a[0].Address != "[127.0.0.1]" {
+		t.Errorf("expected A record addresses to pass through unmodified; got %#v", a)
+	}
+
+	aaaa := addressRecordSets("AAAA", []string{"[2001:DB8::1]"})
+	if len(aaaa) != 1 || aaaa[0].Address != "2001:db8::1" {
+		t.Errorf("expected AAAA record addresses to be stripped of brackets and normalized; got %#v", aaaa)
+	}
+}
+
+func TestZoneMissing(t *testing.T) {
+	meta, closeServer := testMeta(func(w http.ResponseWriter, r *http.Request) {
+		http.NotFound(w, r)
+	})
+	defer closeServer()
+
+	_, err := meta.Client.RecordSet("zone-id", "record-set-id")
+	missing, err := zoneMissing(meta, "zone-id", err)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if !missing {
+		t.Error("expected the zone to be reported missing")
+	}
+}
+
+func TestZoneMissingRecordSetOnly(t *testing.T) {
+	meta, closeServer := testMeta(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/zones/zone-id" {
+			w.Write([]byte(`{"zone":{"id":"zone-id"}}`))
+			return
+		}
+
+		http.NotFound(w, r)
+	})
+	defer closeServer()
+
+	_, err := meta.Client.RecordSet("zone-id", "record-set-id")
+	missing, err := zoneMissing(meta, "zone-id", err)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if missing {
+		t.Error("expected the zone not to be reported missing when only the record set is")
+	}
+}
+
+func TestConfirmRecordSetDeleted(t *testing.T) {
+	meta, closeServer := testMeta(func(w http.ResponseWriter, r *http.Request) {
+		http.NotFound(w, r)
+	})
+	defer closeServer()
+
+	if err := confirmRecordSetDeleted(meta, "zone-id", "record-set-id"); err != nil {
+		t.Errorf("unexpected error for a record set that's gone: %s", err)
+	}
+}
+
+func TestConfirmRecordSetDeletedLingering(t *testing.T) {
+	meta, closeServer := testMeta(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"recordSet":{"id":"record-set-id","status":"Active"}}`))
+	})
+	defer closeServer()
+
+	if err := confirmRecordSetDeleted(meta, "zone-id", "record-set-id"); err == nil {
+		t.Error("expected an error for a record set that still exists")
+	}
+}
+
+func TestWaitUntilRecordSetDeployedTimeout(t *testing.T) {
+	defer func(timeout time.Duration) { recordSetChangeTimeout = timeout }(recordSetChangeTimeout)
+	recordSetChangeTimeout = 100 * time.Millisecond
+
+	meta, closeServer := testMeta(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"id":"change-id","status":"Pending"}`))
+	})
+	defer closeServer()
+
+	d := schema.TestResourceDataRaw(t, resourceVinylDNSRecordSet().Schema, map[string]interface{}{
+		"name":    "terraformtestrecordset",
+		"zone_id": "zone-id",
+		"type":    "A",
+	})
+	d.SetId("record-set-id")
+
+	done := make(chan error, 1)
+	go func() { done <- waitUntilRecordSetDeployed(d, meta, "change-id") }()
+
+	select {
+	case err := <-done:
+		if _, ok := err.(*recordSetChangeTimeoutError); !ok {
+			t.Fatalf("expected a recordSetChangeTimeoutError; got %#v", err)
+		}
+		if !strings.Contains(err.Error(), "change-id") {
+			t.Errorf("expected the timeout error to name the change; got %s", err)
+		}
+	case <-time.After(time.Minute):
+		t.Fatal("waitUntilRecordSetDeployed didn't return once its timeout passed")
+	}
+}
+
+// an apply interrupted after vinyldns created the record set leaves it in the
+// zone but not in state; the next create must adopt it rather than duplicate it
+func TestAdoptableRecordSetInterruptedApply(t *testing.T) {
+	creates := 0
+	meta, closeServer := testMeta(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "POST" {
+			creates++
+		}
+
+		w.Write([]byte(`{"recordSets":[{"id":"record-set-id","zoneId":"zone-id","name":"www","type":"A","ttl":300,"records":[{"address":"127.0.0.1"}]}]}`))
+	})
+	defer closeServer()
+
+	existing, err := adoptableRecordSet(meta, &vinyldns.RecordSet{
+		ZoneID:  "zone-id",
+		Name:    "www",
+		Type:    "A",
+		TTL:     300,
+		Records: []vinyldns.Record{{Address: "127.0.0.1"}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if existing == nil || existing.ID != "record-set-id" {
+		t.Fatalf("expected the existing record set to be adopted; got %#v", existing)
+	}
+	if creates != 0 {
+		t.Errorf("expected no record set to be created; got %d creates", creates)
+	}
+
+	_, err = adoptableRecordSet(meta, &vinyldns.RecordSet{
+		ZoneID:  "zone-id",
+		Name:    "www",
+		Type:    "A",
+		TTL:     300,
+		Records: []vinyldns.Record{{Address: "127.0.0.2"}},
+	})
+	if err == nil || !strings.Contains(err.Error(), "record-set-id") {
+		t.Errorf("expected an error naming the record set holding different data; got %v", err)
+	}
+
+	existing, err = adoptableRecordSet(meta, &vinyldns.RecordSet{ZoneID: "zone-id", Name: "api", Type: "A"})
+	if err != nil || existing != nil {
+		t.Errorf("expected nothing to adopt for a new record set; got %#v, %v", existing, err)
+	}
+}
+
+func TestRecordSetStateRefreshFuncZoneDeleted(t *testing.T) {
+	meta, closeServer := testMeta(func(w http.ResponseWriter, r *http.Request) {
+		http.NotFound(w, r)
+	})
+	defer closeServer()
+
+	_, state, err := recordSetStateRefreshFunc(meta, "zone-id", "record-set-id", "change-id")()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if state != recordSetZoneNotFound {
+		t.Errorf("expected state %s when the zone is gone; got %s", recordSetZoneNotFound, state)
+	}
+}
+
+func TestRecordSetStateRefreshFuncChangeNotFound(t *testing.T) {
+	meta, closeServer := testMeta(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/zones/zone-id" {
+			w.Write([]byte(`{"zone":{"id":"zone-id","name":"system-test."}}`))
+			return
+		}
+
+		http.NotFound(w, r)
+	})
+	defer closeServer()
+
+	_, state, err := recordSetStateRefreshFunc(meta, "zone-id", "record-set-id", "change-id")()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if state != "Pending" {
+		t.Errorf("expected state Pending when only the change is missing; got %s", state)
+	}
+}
+
+func TestRecordSetStateRefreshFuncEmptyStatus(t *testing.T) {
+	meta, closeServer := testMeta(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"id":"change-id","status":""}`))
+	})
+	defer closeServer()
+
+	_, _, err := recordSetStateRefreshFunc(meta, "zone-id", "record-set-id", "change-id")()
+	if err == nil {
+		t.Error("expected an error when vinyldns reports an empty change status")
+	}
+}
+
+func TestStatusTransitions(t *testing.T) {
+	started := time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)
+	transitions := &statusTransitions{}
+
+	cases := []struct {
+		status   string
+		at       time.Duration
+		expected string
+	}{
+		{"", 0, `vinyldns record set change status "" on poll 1, 0s after the previous poll and 0s after the first`},
+		{"Pending", 2 * time.Second, `vinyldns record set change status "" -> "Pending" on poll 2, 2s after the previous poll and 2s after the first`},
+		{"Pending", 5 * time.Second, `vinyldns record set change status "Pending" unchanged on poll 3, 3s after the previous poll and 5s after the first`},
+		{"Complete", 6 * time.Second, `vinyldns record set change status "Pending" -> "Complete" on poll 4, 1s after the previous poll and 6s after the first`},
+	}
+
+	for _, c := range cases {
+		if got := transitions.observe(c.status, started.Add(c.at)); got != c.expected {
+			t.Errorf("expected %s; got %s", c.expected, got)
+		}
+	}
+}
+
+func TestDeletePermitted(t *testing.T) {
+	rs := vinyldns.RecordSet{Name: "api", Type: "A", OwnerGroupID: "owner-group"}
+	acl := func(rules ...vinyldns.ACLRule) *vinyldns.ZoneACL {
+		return &vinyldns.ZoneACL{Rules: rules}
+	}
+
+	cases := []struct {
+		zone     vinyldns.Zone
+		expected bool
+	}{
+		{vinyldns.Zone{AdminGroupID: "member-group"}, true},
+		{vinyldns.Zone{AdminGroupID: "admin-group"}, false},
+		{vinyldns.Zone{AdminGroupID: "admin-group", Shared: true}, false},
+		{vinyldns.Zone{AdminGroupID: "admin-group", ACL: acl(vinyldns.ACLRule{AccessLevel: "Delete", GroupID: "member-group"})}, true},
+		{vinyldns.Zone{AdminGroupID: "admin-group", ACL: acl(vinyldns.ACLRule{AccessLevel: "Write", GroupID: "member-group"})}, false},
+		{vinyldns.Zone{AdminGroupID: "admin-group", ACL: acl(vinyldns.ACLRule{AccessLevel: "Delete", GroupID: "other-group"})}, false},
+		{vinyldns.Zone{AdminGroupID: "admin-group", ACL: acl(vinyldns.ACLRule{AccessLevel: "Delete", UserID: "some-user"})}, true},
+		{vinyldns.Zone{AdminGroupID: "admin-group", ACL: acl(vinyldns.ACLRule{AccessLevel: "Delete"})}, true},
+		{vinyldns.Zone{AdminGroupID: "admin-group", ACL: acl(vinyldns.ACLRule{AccessLevel: "Delete", GroupID: "member-group", RecordTypes: []string{"CNAME"}})}, false},
+		{vinyldns.Zone{AdminGroupID: "admin-group", ACL: acl(vinyldns.ACLRule{AccessLevel: "Delete", GroupID: "member-group", RecordTypes: []string{"A", "AAAA"}, RecordMask: "ap.*"})}, true},
+		{vinyldns.Zone{AdminGroupID: "admin-group", ACL: acl(vinyldns.ACLRule{AccessLevel: "Delete", GroupID: "member-group", RecordMask: "www.*"})}, false},
+	}
+
+	for i, c := range cases {
+		if got := deletePermitted(c.zone, rs, map[string]bool{"member-group": true}); got != c.expected {
+			t.Errorf("case %d: expected %t; got %t", i, c.expected, got)
+		}
+	}
+
+	shared := vinyldns.Zone{AdminGroupID: "admin-group", Shared: true}
+	if !deletePermitted(shared, rs, map[string]bool{"owner-group": true}) {
+		t.Error("expected members of a shared record set's owner group to be permitted")
+	}
+	if !deletePermitted(shared, vinyldns.RecordSet{Name: "api", Type: "A"}, map[string]bool{}) {
+		t.Error("expected unowned record sets of shared zones to be permitted")
+	}
+}
+
+func TestCheckDeletePermission(t *testing.T) {
+	groupLists := 0
+	meta, closeServer := testMeta(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/zones/zone-id":
+			w.Write([]byte(`{"zone":{"id":"zone-id","name":"example.com.","adminGroupId":"admin-group"}}`))
+		case "/zones/zone-id/recordsets/record-set-id":
+			w.Write([]byte(`{"recordSet":{"id":"record-set-id","name":"api","type":"A"}}`))
+		case "/groups":
+			groupLists++
+			w.Write([]byte(`{"groups":[{"id":"member-group"}]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	defer closeServer()
+
+	err := checkDeletePermission(meta, "zone-id", "record-set-id")
+	if err == nil || !strings.Contains(err.Error(), "admin-group") || !strings.Contains(err.Error(), "api") {
+		t.Errorf("expected an error naming the record set and the zone's admin group; got %v", err)
+	}
+
+	if err := checkDeletePermission(meta, "zone-id", "missing-record-set-id"); err != nil {
+		t.Errorf("expected a record set that can't be read to be left to the deletion; got %s", err)
+	}
+
+	if err := checkDeletePermission(meta, "missing-zone-id", "record-set-id"); err == nil {
+		t.Error("expected an error when the zone can't be read")
+	}
+
+	checkDeletePermission(meta, "zone-id", "record-set-id")
+	if groupLists != 1 {
+		t.Errorf("expected the groups to be listed once per run; got %d", groupLists)
+	}
+}
+
 func testAccVinylDNSRecordSetDestroy(s *terraform.State) error {
-	client := testAccProvider.Meta().(*vinyldns.Client)
+	client := testAccProvider.Meta().(*Config).Client
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "vinyldns_record_set" {
@@ -76,7 +1645,7 @@ func testAccCheckVinylDNSRecordSetExists(n string) resource.TestCheckFunc {
 			return fmt.Errorf("No RecordSet ID is set")
 		}
 
-		client := testAccProvider.Meta().(*vinyldns.Client)
+		client := testAccProvider.Meta().(*Config).Client
 		testZId, err := testZoneID()
 		if err != nil {
 			return fmt.Errorf("Error fetching system-test. zone ID")
@@ -99,7 +1668,7 @@ func testAccCheckVinylDNSRecordSetExists(n string) resource.TestCheckFunc {
 }
 
 func testZoneID() (string, error) {
-	client := testAccProvider.Meta().(*vinyldns.Client)
+	client := testAccProvider.Meta().(*Config).Client
 	zones, err := client.ZonesListAll(vinyldns.ListFilter{})
 	if err != nil {
 		return "", err
@@ -137,6 +1706,9 @@ resource "vinyldns_record_set" "test_a_record_set" {
 	type = "A"
 	ttl = 6000
 	record_addresses = ["127.0.0.1", "127.0.0.1"]
+	tags {
+		owner = "dns-team"
+	}
 	depends_on = [
 		"vinyldns_zone.test_zone"
 	]
@@ -174,3 +1746,144 @@ resource "vinyldns_record_set" "test_nsd_record_set" {
 		"vinyldns_zone.test_zone"
 	]
 }`
+
+const testAccVinylDNSRecordSetConfigRecordsJSON = `
+resource "vinyldns_group" "test_group" {
+	name = "terraformtestgroup"
+	description = "some description"
+	email = "tftest@tf.com"
+}
+
+resource "vinyldns_zone" "test_zone" {
+	name = "system-test."
+	email = "foo@bar.com"
+	admin_group_id = "${vinyldns_group.test_group.id}"
+	depends_on = [
+		"vinyldns_group.test_group"
+	]
+}
+
+resource "vinyldns_record_set" "test_srv_record_set" {
+	name = "_sip._tcp.terraformtestrecordset"
+	zone_id = "${vinyldns_zone.test_zone.id}"
+	type = "SRV"
+	ttl = 6000
+	records_json = "[{\"priority\": 10, \"weight\": 5, \"port\": 5060, \"target\": \"sip.system-test.\"}]"
+	depends_on = [
+		"vinyldns_zone.test_zone"
+	]
+}`
+
+const testAccVinylDNSRecordSetConfigLongTXT = `
+resource "vinyldns_group" "test_group" {
+	name = "terraformtestgroup"
+	description = "some description"
+	email = "tftest@tf.com"
+}
+
+resource "vinyldns_zone" "test_zone" {
+	name = "system-test."
+	email = "foo@bar.com"
+	admin_group_id = "${vinyldns_group.test_group.id}"
+	depends_on = [
+		"vinyldns_group.test_group"
+	]
+}
+
+resource "vinyldns_record_set" "test_long_txt_record_set" {
+	name = "long-txt-terraformtestrecordset"
+	zone_id = "${vinyldns_zone.test_zone.id}"
+	type = "TXT"
+	ttl = 6000
+	record_texts = ["%s"]
+	depends_on = [
+		"vinyldns_zone.test_zone"
+	]
+}`
+
+const testAccVinylDNSRecordSetConfigRename = `
+resource "vinyldns_group" "test_group" {
+	name = "terraformtestgroup"
+	description = "some description"
+	email = "tftest@tf.com"
+}
+
+resource "vinyldns_zone" "test_zone" {
+	name = "system-test."
+	email = "foo@bar.com"
+	admin_group_id = "${vinyldns_group.test_group.id}"
+	depends_on = [
+		"vinyldns_group.test_group"
+	]
+}
+
+resource "vinyldns_record_set" "test_rename_record_set" {
+	name = "%s"
+	zone_id = "${vinyldns_zone.test_zone.id}"
+	type = "A"
+	ttl = 6000
+	record_addresses = ["127.0.0.1"]
+	depends_on = [
+		"vinyldns_zone.test_zone"
+	]
+}`
+
+const testAccVinylDNSRecordSetConfigMoveZone = `
+resource "vinyldns_group" "test_group" {
+	name = "terraformtestgroup"
+	description = "some description"
+	email = "tftest@tf.com"
+}
+
+resource "vinyldns_zone" "test_zone" {
+	name = "system-test."
+	email = "foo@bar.com"
+	admin_group_id = "${vinyldns_group.test_group.id}"
+	depends_on = [
+		"vinyldns_group.test_group"
+	]
+}
+
+resource "vinyldns_zone" "other_zone" {
+	name = "ok."
+	email = "foo@bar.com"
+	admin_group_id = "${vinyldns_group.test_group.id}"
+	depends_on = [
+		"vinyldns_group.test_group"
+	]
+}
+
+resource "vinyldns_record_set" "test_move_record_set" {
+	name = "terraformtestrecordset"
+	zone_id = "${vinyldns_zone.%s.id}"
+	type = "A"
+	ttl = 6000
+	record_addresses = ["127.0.0.1"]
+}`
+
+const testAccVinylDNSRecordSetConfigWildcard = `
+resource "vinyldns_group" "test_group" {
+	name = "terraformtestgroup"
+	description = "some description"
+	email = "tftest@tf.com"
+}
+
+resource "vinyldns_zone" "test_zone" {
+	name = "system-test."
+	email = "foo@bar.com"
+	admin_group_id = "${vinyldns_group.test_group.id}"
+	depends_on = [
+		"vinyldns_group.test_group"
+	]
+}
+
+resource "vinyldns_record_set" "test_wildcard_record_set" {
+	name = "*"
+	zone_id = "${vinyldns_zone.test_zone.id}"
+	type = "A"
+	ttl = 6000
+	record_addresses = ["127.0.0.1"]
+	depends_on = [
+		"vinyldns_zone.test_zone"
+	]
+}`
@@ -0,0 +1,507 @@
+/*
+Copyright 2018 Comcast Cable Communications Management, LLC
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vinyldns
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/terraform/helper/hashcode"
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/vinyldns/go-vinyldns/vinyldns"
+)
+
+func resourceVinylDNSRecordSets() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceVinylDNSRecordSetsCreate,
+		Read:   resourceVinylDNSRecordSetsRead,
+		Update: resourceVinylDNSRecordSetsUpdate,
+		Delete: resourceVinylDNSRecordSetsDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceVinylDNSRecordSetsImport,
+		},
+
+		CustomizeDiff: planDefaultZoneID,
+
+		SchemaVersion: 1,
+		MigrateState:  resourceVinylDNSRecordSetsMigrateState,
+
+		Schema: map[string]*schema.Schema{
+			// defaults to the provider's default_zone_id
+			"zone_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+			// whether zone_id was taken from the provider's default_zone_id, so
+			// that a new default moves the record set
+			"zone_id_from_default": &schema.Schema{
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+			"record_set": &schema.Schema{
+				Type:     schema.TypeSet,
+				Required: true,
+				Set:      recordSetsBlockHash,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": &schema.Schema{
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"type": &schema.Schema{
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validateRecordType,
+						},
+						// defaults to the ttl vinyldns assigns
+						"ttl": &schema.Schema{
+							Type:     schema.TypeInt,
+							Optional: true,
+							Computed: true,
+						},
+						"records": &schema.Schema{
+							Type:     schema.TypeSet,
+							Required: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+							Set:      schema.HashString,
+						},
+					},
+				},
+			},
+			// record_set_ids maps each record set's "name:type" key to its vinyldns ID
+			"record_set_ids": &schema.Schema{
+				Type:     schema.TypeMap,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+// recordSetBlock is a single record_set entry of a vinyldns_record_sets resource.
+type recordSetBlock struct {
+	Name    string
+	Type    string
+	TTL     int
+	Records []string
+}
+
+func (b recordSetBlock) key() string {
+	return fmt.Sprintf("%s:%s", b.Name, b.Type)
+}
+
+// record_set entries are identified by name and type alone, so that changing
+// an entry's ttl or records updates its record set rather than replacing it.
+func recordSetsBlockHash(v interface{}) int {
+	m := v.(map[string]interface{})
+	return hashcode.String(fmt.Sprintf("%s:%s", m["name"].(string), m["type"].(string)))
+}
+
+// normalizeRecordValue applies the normalization vinyldns_record_set applies
+// to records of the given type: IPv6 addresses in canonical form and names
+// without their trailing '.'. Other values, TXT text included, are compared as
+// they are. A set's hash doesn't know the type, so records are hashed as they
+// are and compared with this instead.
+func normalizeRecordValue(recordType, value string) string {
+	switch recordType {
+	case "A", "AAAA":
+		return normalizeAddress(value)
+	case "CNAME", "NS", "PTR", "MX", "SRV":
+		return strings.TrimSuffix(value, ".")
+	}
+
+	return value
+}
+
+// recordValuesAsConfigured returns the values read back from vinyldns spelled
+// as in configured wherever normalizeRecordValue finds them the same, so that
+// a value vinyldns returns in canonical form doesn't diff.
+func recordValuesAsConfigured(recordType string, values, configured []string) []string {
+	spelled := map[string]string{}
+	for _, c := range configured {
+		spelled[normalizeRecordValue(recordType, c)] = c
+	}
+
+	result := []string{}
+	for _, v := range values {
+		if c, ok := spelled[normalizeRecordValue(recordType, v)]; ok {
+			v = c
+		}
+		result = append(result, v)
+	}
+
+	return result
+}
+
+func recordSetBlocks(set *schema.Set) map[string]recordSetBlock {
+	blocks := map[string]recordSetBlock{}
+	for _, v := range set.List() {
+		m := v.(map[string]interface{})
+		b := recordSetBlock{
+			Name:    m["name"].(string),
+			Type:    m["type"].(string),
+			TTL:     m["ttl"].(int),
+			Records: stringSetToStringSlice(m["records"].(*schema.Set)),
+		}
+		blocks[b.key()] = b
+	}
+
+	return blocks
+}
+
+func resourceVinylDNSRecordSetsCreate(d *schema.ResourceData, meta interface{}) error {
+	zoneID, err := recordSetZoneID(d, meta)
+	if err != nil {
+		return err
+	}
+	log.Printf("[INFO] Creating vinyldns record sets in zone: %s", zoneID)
+
+	ids := map[string]string{}
+	blocks := recordSetBlocks(d.Get("record_set").(*schema.Set))
+	err = applyRecordSetBlocks(meta, zoneID, ids, blocks, nil, nil)
+
+	// the zone's ID alone would collide with other vinyldns_record_sets in it
+	d.SetId(resource.UniqueId())
+	d.Set("record_set_ids", ids)
+	if err != nil {
+		return err
+	}
+
+	return resourceVinylDNSRecordSetsRead(d, meta)
+}
+
+func resourceVinylDNSRecordSetsRead(d *schema.ResourceData, meta interface{}) error {
+	zoneID := d.Get("zone_id").(string)
+	log.Printf("[INFO] Reading vinyldns record sets in zone: %s", zoneID)
+	client := meta.(*Config).Client
+
+	configured := recordSetBlocks(d.Get("record_set").(*schema.Set))
+	ids := map[string]string{}
+	blocks := []interface{}{}
+	for key, id := range d.Get("record_set_ids").(map[string]interface{}) {
+		rs, err := client.RecordSet(zoneID, id.(string))
+		if err != nil {
+			if dErr, ok := err.(*vinyldns.Error); ok && dErr.ResponseCode == http.StatusNotFound {
+				log.Printf("[WARN] vinyldns record set %s (%s) not found; removing from state", key, id)
+				continue
+			}
+
+			return err
+		}
+
+		ids[key] = rs.ID
+		blocks = append(blocks, map[string]interface{}{
+			"name":    rs.Name,
+			"type":    rs.Type,
+			"ttl":     rs.TTL,
+			"records": recordValuesAsConfigured(rs.Type, recordValues(rs), configured[key].Records),
+		})
+	}
+
+	d.Set("record_set_ids", ids)
+
+	return d.Set("record_set", blocks)
+}
+
+func resourceVinylDNSRecordSetsUpdate(d *schema.ResourceData, meta interface{}) error {
+	zoneID := d.Get("zone_id").(string)
+	log.Printf("[INFO] Updating vinyldns record sets in zone: %s", zoneID)
+
+	ids := map[string]string{}
+	for key, id := range d.Get("record_set_ids").(map[string]interface{}) {
+		ids[key] = id.(string)
+	}
+
+	o, n := d.GetChange("record_set")
+	oldBlocks := recordSetBlocks(o.(*schema.Set))
+	newBlocks := recordSetBlocks(n.(*schema.Set))
+
+	creates := map[string]recordSetBlock{}
+	updates := map[string]recordSetBlock{}
+	deletes := []string{}
+	for key, b := range newBlocks {
+		if _, ok := ids[key]; !ok {
+			creates[key] = b
+			continue
+		}
+
+		if !recordSetBlocksEqual(oldBlocks[key], b) {
+			updates[key] = b
+		}
+	}
+	for key := range ids {
+		if _, ok := newBlocks[key]; !ok {
+			deletes = append(deletes, key)
+		}
+	}
+
+	err := applyRecordSetBlocks(meta, zoneID, ids, creates, updates, deletes)
+
+	d.Set("record_set_ids", ids)
+	if err != nil {
+		return err
+	}
+
+	return resourceVinylDNSRecordSetsRead(d, meta)
+}
+
+func resourceVinylDNSRecordSetsDelete(d *schema.ResourceData, meta interface{}) error {
+	zoneID := d.Get("zone_id").(string)
+	log.Printf("[INFO] Deleting vinyldns record sets in zone: %s", zoneID)
+
+	ids := map[string]string{}
+	deletes := []string{}
+	for key, id := range d.Get("record_set_ids").(map[string]interface{}) {
+		ids[key] = id.(string)
+		deletes = append(deletes, key)
+	}
+
+	err := applyRecordSetBlocks(meta, zoneID, ids, nil, nil, deletes)
+	if err != nil {
+		d.Set("record_set_ids", ids)
+		return err
+	}
+
+	d.SetId("")
+
+	return nil
+}
+
+// resourceVinylDNSRecordSetsImport imports the record sets named by an ID of
+// the form zone_id:record_set_id,record_set_id,...
+func resourceVinylDNSRecordSetsImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	zoneID, recordSetIDs, err := parseRecordSetsImportID(d.Id())
+	if err != nil {
+		return nil, err
+	}
+
+	client := meta.(*Config).Client
+	ids := map[string]string{}
+	for _, id := range recordSetIDs {
+		rs, err := client.RecordSet(zoneID, id)
+		if err != nil {
+			return nil, err
+		}
+
+		ids[recordSetBlock{Name: rs.Name, Type: rs.Type}.key()] = rs.ID
+	}
+
+	d.SetId(resource.UniqueId())
+	d.Set("zone_id", zoneID)
+	d.Set("zone_id_from_default", false)
+	d.Set("record_set_ids", ids)
+
+	return []*schema.ResourceData{d}, nil
+}
+
+func parseRecordSetsImportID(id string) (string, []string, error) {
+	parts := strings.SplitN(id, ":", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", nil, fmt.Errorf("record sets import IDs take the form zone_id:record_set_id,record_set_id,...; got %q", id)
+	}
+
+	recordSetIDs := strings.Split(parts[1], ",")
+	for _, rsID := range recordSetIDs {
+		if rsID == "" {
+			return "", nil, fmt.Errorf("record sets import IDs take the form zone_id:record_set_id,record_set_id,...; got %q", id)
+		}
+	}
+
+	return parts[0], recordSetIDs, nil
+}
+
+// applyRecordSetBlocks submits the creates, updates and deletes as individual
+// record set changes, at most MaxConcurrency at a time, and waits for each to
+// complete. The deletes complete before any create or update is submitted, so
+// that a record set replaced by one of another type under the same name, such
+// as an A record set by a CNAME, is gone before its replacement is created.
+// ids is kept current with every change that succeeds, so callers can record
+// partial progress even when an error is returned.
+func applyRecordSetBlocks(meta interface{}, zoneID string, ids map[string]string, creates, updates map[string]recordSetBlock, deletes []string) error {
+	config := meta.(*Config)
+	client := config.Client
+
+	var mu sync.Mutex
+	err := config.forEach(len(deletes), func(i int) error {
+		key := deletes[i]
+
+		mu.Lock()
+		id := ids[key]
+		mu.Unlock()
+
+		deleted, err := client.RecordSetDelete(zoneID, id)
+		if err != nil {
+			return err
+		}
+
+		err = waitUntilRecordSetChangeDeleted(meta, zoneID, id, deleted.ChangeID)
+		if err != nil {
+			return err
+		}
+		config.Metrics.inc(metricRecordSetsDeleted)
+
+		mu.Lock()
+		delete(ids, key)
+		mu.Unlock()
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	type op struct {
+		key   string
+		block recordSetBlock
+	}
+
+	ops := []op{}
+	for key, b := range creates {
+		ops = append(ops, op{key: key, block: b})
+	}
+	for key, b := range updates {
+		ops = append(ops, op{key: key, block: b})
+	}
+
+	return config.forEach(len(ops), func(i int) error {
+		o := ops[i]
+
+		mu.Lock()
+		id, exists := ids[o.key]
+		mu.Unlock()
+
+		records, err := typedRecords(o.block.Type, o.block.Records)
+		if err == nil {
+			err = validateRecords(o.block.Type, records)
+		}
+		if err == nil {
+			err = checkRecordCount(len(records), config.MaxRecordSetEntries)
+		}
+		if err != nil {
+			return fmt.Errorf("record_set %s: %s", o.key, err)
+		}
+
+		rs := &vinyldns.RecordSet{
+			ID:      id,
+			Name:    o.block.Name,
+			ZoneID:  zoneID,
+			Type:    o.block.Type,
+			TTL:     o.block.TTL,
+			Records: records,
+		}
+
+		var change *vinyldns.RecordSetUpdateResponse
+		if exists {
+			change, err = client.RecordSetUpdate(rs)
+		} else {
+			change, err = client.RecordSetCreate(rs)
+		}
+		if err != nil {
+			return err
+		}
+
+		mu.Lock()
+		ids[o.key] = change.RecordSet.ID
+		mu.Unlock()
+
+		if err := waitUntilRecordSetChangeDeployed(meta, zoneID, change.RecordSet.ID, change.ChangeID); err != nil {
+			return err
+		}
+
+		if exists {
+			config.Metrics.inc(metricRecordSetsUpdated)
+		} else {
+			config.Metrics.inc(metricRecordSetsCreated)
+		}
+
+		return nil
+	})
+}
+
+func recordSetBlocksEqual(a, b recordSetBlock) bool {
+	if a.TTL != b.TTL || len(a.Records) != len(b.Records) {
+		return false
+	}
+
+	values := map[string]bool{}
+	for _, r := range a.Records {
+		values[normalizeRecordValue(a.Type, r)] = true
+	}
+	for _, r := range b.Records {
+		if !values[normalizeRecordValue(b.Type, r)] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// typedRecords converts plain string values to the records of the given type.
+func typedRecords(recordType string, values []string) ([]vinyldns.Record, error) {
+	switch recordType {
+	case "SOA":
+		return []vinyldns.Record{}, ErrUnsupportedType
+	case "CNAME":
+		if len(values) != 1 {
+			return []vinyldns.Record{}, errors.New("CNAME record sets require exactly one record")
+		}
+
+		if !strings.HasSuffix(values[0], ".") {
+			return []vinyldns.Record{}, ErrTrailingDotRequired
+		}
+
+		return []vinyldns.Record{
+			vinyldns.Record{
+				CName: values[0],
+			},
+		}, nil
+	case "TXT":
+		return txtRecordSets(values), nil
+	case "NS":
+		return nsRecordSets(values), nil
+	case "PTR":
+		return ptrRecordSets(values), nil
+	case "A", "AAAA":
+		return addressRecordSets(recordType, values), nil
+	}
+
+	return []vinyldns.Record{}, fmt.Errorf("%s record sets are not supported by vinyldns_record_sets; use vinyldns_record_set", recordType)
+}
+
+// recordValues returns the string value of each of the record set's records.
+func recordValues(rs vinyldns.RecordSet) []string {
+	values := []string{}
+	for _, r := range rs.Records {
+		switch rs.Type {
+		case "CNAME":
+			values = append(values, r.CName)
+		case "TXT":
+			values = append(values, r.Text)
+		case "NS":
+			values = append(values, r.NSDName)
+		case "PTR":
+			values = append(values, r.PTRDName)
+		default:
+			values = append(values, r.Address)
+		}
+	}
+
+	return values
+}
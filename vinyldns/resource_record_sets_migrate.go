@@ -0,0 +1,50 @@
+/*
+Copyright 2018 Comcast Cable Communications Management, LLC
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vinyldns
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+// resourceVinylDNSRecordSetsMigrateState upgrades record sets state written by
+// earlier versions of the provider to the current schema version.
+func resourceVinylDNSRecordSetsMigrateState(v int, is *terraform.InstanceState, meta interface{}) (*terraform.InstanceState, error) {
+	switch v {
+	case 0:
+		log.Println("[INFO] Found vinyldns record sets state v0; migrating to v1")
+		return migrateRecordSetsStateV0toV1(is)
+	default:
+		return is, fmt.Errorf("unexpected vinyldns record sets schema version: %d", v)
+	}
+}
+
+// migrateRecordSetsStateV0toV1 gives record sets identified by their zone's ID
+// an ID of their own, keeping the zone's ID in zone_id, where it's read from.
+func migrateRecordSetsStateV0toV1(is *terraform.InstanceState) (*terraform.InstanceState, error) {
+	if is.Empty() || is.Attributes == nil {
+		log.Println("[DEBUG] empty vinyldns record sets state; nothing to migrate")
+		return is, nil
+	}
+
+	if is.Attributes["zone_id"] == "" {
+		is.Attributes["zone_id"] = is.ID
+	}
+	is.ID = resource.UniqueId()
+	is.Attributes["id"] = is.ID
+
+	return is, nil
+}
@@ -0,0 +1,48 @@
+/*
+Copyright 2018 Comcast Cable Communications Management, LLC
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vinyldns
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestResourceVinylDNSRecordSetsMigrateStateV0toV1(t *testing.T) {
+	is := &terraform.InstanceState{
+		ID: "zone-id",
+		Attributes: map[string]string{
+			"id":               "zone-id",
+			"record_set.#":     "1",
+			"record_set_ids.%": "1",
+		},
+	}
+
+	is, err := resourceVinylDNSRecordSetsMigrateState(0, is, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if is.ID == "zone-id" || is.Attributes["id"] != is.ID {
+		t.Errorf("expected record sets to get an ID of their own; got %q", is.ID)
+	}
+	if is.Attributes["zone_id"] != "zone-id" {
+		t.Errorf("expected zone_id to be the zone's ID; got %q", is.Attributes["zone_id"])
+	}
+}
+
+func TestResourceVinylDNSRecordSetsMigrateStateEmpty(t *testing.T) {
+	if _, err := resourceVinylDNSRecordSetsMigrateState(0, &terraform.InstanceState{}, nil); err != nil {
+		t.Fatalf("unexpected error migrating empty state: %s", err)
+	}
+}
@@ -0,0 +1,291 @@
+/*
+Copyright 2018 Comcast Cable Communications Management, LLC
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vinyldns
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestAccVinylDNSRecordSetsBasic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccVinylDNSRecordSetsDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccVinylDNSRecordSetsConfigBasic,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("vinyldns_record_sets.test_record_sets", "record_set.#", "3"),
+					resource.TestCheckResourceAttr("vinyldns_record_sets.test_record_sets", "record_set_ids.%", "3"),
+					resource.TestCheckResourceAttrSet("vinyldns_record_sets.test_record_sets", "record_set_ids.a-terraformtestrecordset:A"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccVinylDNSRecordSetsImport(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccVinylDNSRecordSetsDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccVinylDNSRecordSetsConfigBasic,
+			},
+			resource.TestStep{
+				ResourceName:      "vinyldns_record_sets.test_record_sets",
+				ImportState:       true,
+				ImportStateVerify: true,
+				ImportStateIdFunc: testAccVinylDNSRecordSetsImportID("vinyldns_record_sets.test_record_sets"),
+			},
+			resource.TestStep{
+				Config:   testAccVinylDNSRecordSetsConfigBasic,
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func testAccVinylDNSRecordSetsImportID(n string) resource.ImportStateIdFunc {
+	return func(s *terraform.State) (string, error) {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return "", fmt.Errorf("Not found: %s", n)
+		}
+
+		ids := []string{}
+		for k, v := range rs.Primary.Attributes {
+			if strings.HasPrefix(k, "record_set_ids.") && k != "record_set_ids.%" {
+				ids = append(ids, v)
+			}
+		}
+
+		return rs.Primary.Attributes["zone_id"] + ":" + strings.Join(ids, ","), nil
+	}
+}
+
+func TestParseRecordSetsImportID(t *testing.T) {
+	zoneID, recordSetIDs, err := parseRecordSetsImportID("zone-id:rs-1,rs-2")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if zoneID != "zone-id" || len(recordSetIDs) != 2 || recordSetIDs[0] != "rs-1" || recordSetIDs[1] != "rs-2" {
+		t.Errorf("expected zone-id and rs-1,rs-2; got %s and %v", zoneID, recordSetIDs)
+	}
+
+	for _, id := range []string{"", "rs-1", ":rs-1", "zone-id:", "zone-id:rs-1,"} {
+		if _, _, err := parseRecordSetsImportID(id); err == nil {
+			t.Errorf("expected an error for import ID %q", id)
+		}
+	}
+}
+
+func TestNormalizeRecordValue(t *testing.T) {
+	equivalent := [][3]string{
+		{"AAAA", "2001:DB8:0:0:0:0:0:1", "2001:db8::1"},
+		{"AAAA", "[2001:db8::1]", "2001:db8::1"},
+		{"NS", "ns1.example.com", "ns1.example.com."},
+		{"CNAME", "www.example.com", "www.example.com."},
+	}
+	for _, values := range equivalent {
+		if normalizeRecordValue(values[0], values[1]) != normalizeRecordValue(values[0], values[2]) {
+			t.Errorf("expected %s records %q and %q to match", values[0], values[1], values[2])
+		}
+	}
+
+	// a trailing dot is part of TXT text
+	if normalizeRecordValue("TXT", "the end.") == normalizeRecordValue("TXT", "the end") {
+		t.Error("expected TXT text to keep its trailing dot")
+	}
+}
+
+func TestRecordValuesAsConfigured(t *testing.T) {
+	got := recordValuesAsConfigured("CNAME", []string{"www.example.com."}, []string{"www.example.com"})
+	if !reflect.DeepEqual(got, []string{"www.example.com"}) {
+		t.Errorf("expected the configured spelling; got %v", got)
+	}
+
+	got = recordValuesAsConfigured("TXT", []string{"the end.", "new"}, []string{"the end"})
+	if !reflect.DeepEqual(got, []string{"the end.", "new"}) {
+		t.Errorf("expected values vinyldns holds otherwise to be read back as they are; got %v", got)
+	}
+}
+
+func TestRecordSetBlocksEqual(t *testing.T) {
+	a := recordSetBlock{Name: "www", Type: "CNAME", TTL: 300, Records: []string{"www.example.com."}}
+	b := recordSetBlock{Name: "www", Type: "CNAME", TTL: 300, Records: []string{"www.example.com"}}
+	if !recordSetBlocksEqual(a, b) {
+		t.Error("expected names differing only by the trailing dot to be equal")
+	}
+
+	a = recordSetBlock{Name: "txt", Type: "TXT", TTL: 300, Records: []string{"the end."}}
+	b = recordSetBlock{Name: "txt", Type: "TXT", TTL: 300, Records: []string{"the end"}}
+	if recordSetBlocksEqual(a, b) {
+		t.Error("expected TXT text differing by a trailing dot to differ")
+	}
+}
+
+func TestApplyRecordSetBlocksDeletesFirst(t *testing.T) {
+	var mu sync.Mutex
+	events := []string{}
+	meta, closeServer := testMeta(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch {
+		case r.Method == "DELETE" && r.URL.Path == "/zones/zone-id/recordsets/a-id":
+			events = append(events, "delete")
+			w.Write([]byte(`{"id":"delete-change-id"}`))
+		case r.Method == "POST" && r.URL.Path == "/zones/zone-id/recordsets":
+			events = append(events, "create")
+			w.Write([]byte(`{"id":"create-change-id","recordSet":{"id":"cname-id"}}`))
+		case r.URL.Path == "/zones/zone-id/recordsets/a-id/changes/delete-change-id":
+			events = append(events, "deleted")
+			w.Write([]byte(`{"id":"delete-change-id","status":"Complete"}`))
+		case r.URL.Path == "/zones/zone-id/recordsets/cname-id/changes/create-change-id":
+			w.Write([]byte(`{"id":"create-change-id","status":"Complete"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	defer closeServer()
+	meta.MaxConcurrency = 2
+
+	ids := map[string]string{"www:A": "a-id"}
+	creates := map[string]recordSetBlock{
+		"www:CNAME": recordSetBlock{Name: "www", Type: "CNAME", TTL: 300, Records: []string{"web.example.com."}},
+	}
+	if err := applyRecordSetBlocks(meta, "zone-id", ids, creates, nil, []string{"www:A"}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	// a CNAME can't be created alongside the A record set it replaces
+	if !reflect.DeepEqual(events, []string{"delete", "deleted", "create"}) {
+		t.Errorf("expected the A record set to be deleted before the CNAME is created; got %v", events)
+	}
+	if !reflect.DeepEqual(ids, map[string]string{"www:CNAME": "cname-id"}) {
+		t.Errorf("unexpected record set IDs: %v", ids)
+	}
+}
+
+func TestTypedRecords(t *testing.T) {
+	records, err := typedRecords("A", []string{"127.0.0.1", "127.0.0.2"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(records) != 2 || records[0].Address != "127.0.0.1" {
+		t.Errorf("unexpected A records: %#v", records)
+	}
+
+	records, err = typedRecords("CNAME", []string{"foo.bar."})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(records) != 1 || records[0].CName != "foo.bar." {
+		t.Errorf("unexpected CNAME records: %#v", records)
+	}
+
+	if _, err := typedRecords("CNAME", []string{"foo.bar"}); err != ErrTrailingDotRequired {
+		t.Error("expected an error for a CNAME without a trailing dot")
+	}
+
+	if _, err := typedRecords("CNAME", []string{"foo.bar.", "baz.bar."}); err == nil {
+		t.Error("expected an error for a CNAME with more than one record")
+	}
+
+	if _, err := typedRecords("SOA", []string{"foo"}); err != ErrUnsupportedType {
+		t.Error("expected an error for an SOA record")
+	}
+
+	if _, err := typedRecords("SRV", []string{"foo"}); err == nil {
+		t.Error("expected an error for a record type without string values")
+	}
+}
+
+func testAccVinylDNSRecordSetsDestroy(s *terraform.State) error {
+	client := testAccProvider.Meta().(*Config).Client
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "vinyldns_record_sets" {
+			continue
+		}
+
+		for key, value := range rs.Primary.Attributes {
+			if !strings.HasPrefix(key, "record_set_ids.") || key == "record_set_ids.%" {
+				continue
+			}
+
+			// Try to find the record set
+			_, err := client.RecordSet(rs.Primary.ID, value)
+			if err == nil {
+				return fmt.Errorf("RecordSet %s still exists", value)
+			}
+		}
+	}
+
+	return nil
+}
+
+const testAccVinylDNSRecordSetsConfigBasic = `
+resource "vinyldns_group" "test_group" {
+	name = "terraformtestgroup"
+	description = "some description"
+	email = "tftest@tf.com"
+}
+
+resource "vinyldns_zone" "test_zone" {
+	name = "system-test."
+	email = "foo@bar.com"
+	admin_group_id = "${vinyldns_group.test_group.id}"
+	depends_on = [
+		"vinyldns_group.test_group"
+	]
+}
+
+resource "vinyldns_record_sets" "test_record_sets" {
+	zone_id = "${vinyldns_zone.test_zone.id}"
+
+	record_set {
+		name = "a-terraformtestrecordset"
+		type = "A"
+		ttl = 6000
+		records = ["127.0.0.1", "127.0.0.2"]
+	}
+
+	record_set {
+		name = "cname-terraformtestrecordset"
+		type = "CNAME"
+		ttl = 6000
+		records = ["a-terraformtestrecordset.system-test."]
+	}
+
+	record_set {
+		name = "txt-terraformtestrecordset"
+		type = "TXT"
+		ttl = 6000
+		records = ["Lorem ipsum and all that jazz"]
+	}
+
+	depends_on = [
+		"vinyldns_zone.test_zone"
+	]
+}`
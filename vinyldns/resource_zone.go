@@ -14,7 +14,10 @@ package vinyldns
 
 import (
 	"errors"
+	"fmt"
 	"log"
+	"net/http"
+	"net/mail"
 	"time"
 
 	"github.com/hashicorp/terraform/helper/resource"
@@ -29,18 +32,37 @@ func resourceVinylDNSZone() *schema.Resource {
 		Update: resourceVinylDNSZoneUpdate,
 		Delete: resourceVinylDNSZoneDelete,
 
+		// zone changes, especially those that sync the zone, can take far
+		// longer than record set changes
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(60 * time.Minute),
+			Update: schema.DefaultTimeout(60 * time.Minute),
+			Delete: schema.DefaultTimeout(60 * time.Minute),
+		},
+
 		Schema: map[string]*schema.Schema{
 			"name": &schema.Schema{
 				Type:     schema.TypeString,
 				Required: true,
 			},
+			// falls back to the provider's default_zone_email when omitted
 			"email": &schema.Schema{
-				Type:     schema.TypeString,
-				Required: true,
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateEmail,
 			},
+			// one of admin_group_id or admin_group_name is required; a name is
+			// resolved to the group's ID, which is what's kept in state
 			"admin_group_id": &schema.Schema{
-				Type:     schema.TypeString,
-				Required: true,
+				Type:          schema.TypeString,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: []string{"admin_group_name"},
+			},
+			"admin_group_name": &schema.Schema{
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"admin_group_id"},
 			},
 			"status": &schema.Schema{
 				Type:     schema.TypeString,
@@ -54,6 +76,12 @@ func resourceVinylDNSZone() *schema.Resource {
 				Type:     schema.TypeString,
 				Computed: true,
 			},
+			// vinyldns zones have no default ttl, so this lives only in terraform state
+			// for record sets to reference
+			"default_record_ttl": &schema.Schema{
+				Type:     schema.TypeInt,
+				Optional: true,
+			},
 			"transfer_connection": connectionSchema(),
 			"zone_connection":     connectionSchema(),
 		},
@@ -63,11 +91,18 @@ func resourceVinylDNSZone() *schema.Resource {
 func resourceVinylDNSZoneCreate(d *schema.ResourceData, meta interface{}) error {
 	name := d.Get("name").(string)
 	log.Printf("[INFO] Creating vinyldns zone: %s", name)
-	change, err := meta.(*vinyldns.Client).ZoneCreate(zone(d))
+	z, err := zone(d, meta)
+	if err != nil {
+		return err
+	}
+
+	change, err := meta.(*Config).Client.ZoneCreate(z)
 	if err != nil {
 		return err
 	}
 
+	d.Set("admin_group_id", z.AdminGroupID)
+
 	log.Printf("[INFO] Setting *schema.ResourceData zone ID to: %s", change.Zone.ID)
 
 	d.SetId(change.Zone.ID)
@@ -84,9 +119,9 @@ func resourceVinylDNSZoneCreate(d *schema.ResourceData, meta interface{}) error
 
 func resourceVinylDNSZoneRead(d *schema.ResourceData, meta interface{}) error {
 	log.Printf("[INFO] Reading vinyldns zone: %s", d.Id())
-	zone, err := meta.(*vinyldns.Client).Zone(d.Id())
+	zone, err := meta.(*Config).Client.Zone(d.Id())
 	if err != nil {
-		return err
+		return zoneAccessError(err, d.Id())
 	}
 
 	d.Set("name", zone.Name)
@@ -96,12 +131,35 @@ func resourceVinylDNSZoneRead(d *schema.ResourceData, meta interface{}) error {
 
 func resourceVinylDNSZoneUpdate(d *schema.ResourceData, meta interface{}) error {
 	log.Printf("[INFO] Updating vinyldns zone: %s", d.Id())
-	change, err := meta.(*vinyldns.Client).ZoneUpdate(d.Id(), zone(d))
+	z, err := zone(d, meta)
+	if err != nil {
+		return err
+	}
+
+	// the ACL is managed by vinyldns_zone_acl, so keep whatever rules the zone has
+	existing, err := meta.(*Config).Client.Zone(d.Id())
+	if err != nil {
+		return err
+	}
+	z.ACL = existing.ACL
+
+	// likewise connections the zone has never configured inline may be
+	// managed by vinyldns_zone_connection
+	if !d.HasChange("zone_connection") && d.Get("zone_connection.0.name").(string) == "" {
+		z.Connection = existing.Connection
+	}
+	if !d.HasChange("transfer_connection") && d.Get("transfer_connection.0.name").(string) == "" {
+		z.TransferConnection = existing.TransferConnection
+	}
+
+	change, err := meta.(*Config).Client.ZoneUpdate(d.Id(), z)
 	if err != nil {
 		return err
 	}
 
-	err = waitUntilZoneChangeDeployed(d, meta, change.Zone.ID)
+	d.Set("admin_group_id", z.AdminGroupID)
+
+	err = waitUntilZoneChangeDeployed(d, meta, change.Zone.ID, d.Timeout(schema.TimeoutUpdate))
 	if err != nil {
 		return err
 	}
@@ -144,7 +202,7 @@ func transferConnection(d *schema.ResourceData) *vinyldns.ZoneConnection {
 func resourceVinylDNSZoneDelete(d *schema.ResourceData, meta interface{}) error {
 	log.Printf("[INFO] Deleting vinyldns zone: %s", d.Id())
 
-	_, err := meta.(*vinyldns.Client).ZoneDelete(d.Id())
+	_, err := meta.(*Config).Client.ZoneDelete(d.Id())
 	if err != nil {
 		return err
 	}
@@ -159,12 +217,12 @@ func resourceVinylDNSZoneDelete(d *schema.ResourceData, meta interface{}) error
 	return nil
 }
 
-func waitUntilZoneChangeDeployed(d *schema.ResourceData, meta interface{}, changeID string) error {
+func waitUntilZoneChangeDeployed(d *schema.ResourceData, meta interface{}, changeID string, timeout time.Duration) error {
 	stateConf := &resource.StateChangeConf{
 		Pending:      []string{"Pending", ""},
 		Target:       []string{"Synced"},
 		Refresh:      zoneStateRefreshFunc(d, meta, changeID),
-		Timeout:      30 * time.Minute,
+		Timeout:      timeout,
 		Delay:        500 * time.Millisecond,
 		MinTimeout:   15 * time.Second,
 		PollInterval: 500 * time.Millisecond,
@@ -176,8 +234,9 @@ func waitUntilZoneChangeDeployed(d *schema.ResourceData, meta interface{}, chang
 
 func zoneStateRefreshFunc(d *schema.ResourceData, meta interface{}, changeID string) resource.StateRefreshFunc {
 	return func() (interface{}, string, error) {
-		log.Printf("[INFO] waiting for Complete status of %v, %s", d.Get("name"), d.Id())
-		zc, err := meta.(*vinyldns.Client).ZoneChange(d.Id(), changeID)
+		log.Printf("[DEBUG] Polling vinyldns zone change of %v; %s", d.Get("name"), logFields("zone_id", d.Id(), "change_id", changeID))
+		meta.(*Config).Metrics.inc(metricPollCycles)
+		zc, err := meta.(*Config).Client.ZoneChange(d.Id(), changeID)
 		if err != nil {
 			log.Printf("[ERROR] %#v", err)
 			return nil, "", err
@@ -197,7 +256,7 @@ func waitUntilZoneDeleted(d *schema.ResourceData, meta interface{}, zoneID strin
 		Pending:      []string{"Pending"},
 		Target:       []string{"Deleted"},
 		Refresh:      zoneDeletedStateRefreshFunc(d, meta, zoneID),
-		Timeout:      30 * time.Minute,
+		Timeout:      d.Timeout(schema.TimeoutDelete),
 		Delay:        500 * time.Millisecond,
 		MinTimeout:   15 * time.Second,
 		PollInterval: 500 * time.Millisecond,
@@ -211,8 +270,9 @@ func zoneDeletedStateRefreshFunc(d *schema.ResourceData, meta interface{}, zoneI
 	return func() (interface{}, string, error) {
 		state := "Pending"
 
-		log.Printf("[INFO] waiting for successful deletion of %v, %s", d.Get("name"), d.Id())
-		exists, err := meta.(*vinyldns.Client).ZoneExists(d.Id())
+		log.Printf("[DEBUG] Polling for deletion of vinyldns zone %v; %s", d.Get("name"), logFields("zone_id", d.Id()))
+		meta.(*Config).Metrics.inc(metricPollCycles)
+		exists, err := meta.(*Config).Client.ZoneExists(d.Id())
 		if err != nil {
 			log.Printf("[ERROR] %#v", err)
 			return nil, "", err
@@ -231,7 +291,7 @@ func waitUntilZoneCreated(d *schema.ResourceData, meta interface{}) error {
 		Pending:      []string{"Pending"},
 		Target:       []string{"Created"},
 		Refresh:      zoneCreatedStateRefreshFunc(d, meta),
-		Timeout:      30 * time.Minute,
+		Timeout:      d.Timeout(schema.TimeoutCreate),
 		Delay:        500 * time.Millisecond,
 		MinTimeout:   15 * time.Second,
 		PollInterval: 500 * time.Millisecond,
@@ -245,8 +305,9 @@ func zoneCreatedStateRefreshFunc(d *schema.ResourceData, meta interface{}) resou
 	return func() (interface{}, string, error) {
 		state := "Pending"
 
-		log.Printf("[INFO] waiting for successful creation of %v, %s", d.Get("name"), d.Id())
-		exists, err := meta.(*vinyldns.Client).ZoneExists(d.Id())
+		log.Printf("[DEBUG] Polling for creation of vinyldns zone %v; %s", d.Get("name"), logFields("zone_id", d.Id()))
+		meta.(*Config).Metrics.inc(metricPollCycles)
+		exists, err := meta.(*Config).Client.ZoneExists(d.Id())
 		if err != nil {
 			log.Printf("[ERROR] %#v", err)
 			return nil, "", err
@@ -260,6 +321,31 @@ func zoneCreatedStateRefreshFunc(d *schema.ResourceData, meta interface{}) resou
 	}
 }
 
+// zoneAccessError explains the 403 vinyldns returns for a zone the provider's
+// credentials have no access to, which is easily mistaken for the zone not
+// existing. Other errors are returned as they are.
+func zoneAccessError(err error, zoneID string) error {
+	dErr, ok := err.(*vinyldns.Error)
+	if !ok || dErr.ResponseCode != http.StatusForbidden {
+		return err
+	}
+
+	return fmt.Errorf("not authorized to access zone %s; the zone exists, but the provider's credentials are neither in its "+
+		"admin group nor granted access by one of its ACL rules. Check the zone's admin group membership and ACL rules: %s", zoneID, err)
+}
+
+// validateEmail ensures the value is a bare, well-formed email address.
+func validateEmail(v interface{}, k string) (ws []string, es []error) {
+	value := v.(string)
+
+	addr, err := mail.ParseAddress(value)
+	if err != nil || addr.Address != value {
+		es = append(es, fmt.Errorf("%q must be a valid email address; got %q", k, value))
+	}
+
+	return
+}
+
 type zoneState struct {
 	State string
 }
@@ -291,11 +377,33 @@ func connectionSchema() *schema.Schema {
 	}
 }
 
-func zone(d *schema.ResourceData) *vinyldns.Zone {
+func zone(d *schema.ResourceData, meta interface{}) (*vinyldns.Zone, error) {
+	email := d.Get("email").(string)
+	if email == "" {
+		email = meta.(*Config).DefaultZoneEmail
+	}
+
+	if email == "" {
+		return nil, errors.New("email must be set on the zone or default_zone_email on the provider")
+	}
+
+	adminGroupID := d.Get("admin_group_id").(string)
+	if name := d.Get("admin_group_name").(string); name != "" {
+		id, err := groupIDByName(meta, name)
+		if err != nil {
+			return nil, err
+		}
+		adminGroupID = id
+	}
+
+	if adminGroupID == "" {
+		return nil, errors.New("one of admin_group_id or admin_group_name must be set")
+	}
+
 	zone := &vinyldns.Zone{
 		Name:         d.Get("name").(string),
-		Email:        d.Get("email").(string),
-		AdminGroupID: d.Get("admin_group_id").(string),
+		Email:        email,
+		AdminGroupID: adminGroupID,
 	}
 
 	if d.Get("zone_connection.0.name").(string) != "" {
@@ -311,5 +419,5 @@ func zone(d *schema.ResourceData) *vinyldns.Zone {
 		zone.Shared = shared
 	}
 
-	return zone
+	return zone, nil
 }
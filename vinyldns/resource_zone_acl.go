@@ -0,0 +1,217 @@
+/*
+Copyright 2018 Comcast Cable Communications Management, LLC
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vinyldns
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/vinyldns/go-vinyldns/vinyldns"
+)
+
+func resourceVinylDNSZoneACL() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceVinylDNSZoneACLCreate,
+		Read:   resourceVinylDNSZoneACLRead,
+		Update: resourceVinylDNSZoneACLUpdate,
+		Delete: resourceVinylDNSZoneACLDelete,
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(30 * time.Minute),
+			Update: schema.DefaultTimeout(30 * time.Minute),
+			Delete: schema.DefaultTimeout(30 * time.Minute),
+		},
+
+		Schema: map[string]*schema.Schema{
+			// a zone has exactly one ACL, so the ACL is a new one for a new zone
+			"zone_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			// a list rather than a set, as rules may be evaluated in order
+			"rule": &schema.Schema{
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"access_level": &schema.Schema{
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validateAccessLevel,
+						},
+						"description": &schema.Schema{
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"user_id": &schema.Schema{
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"group_id": &schema.Schema{
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"record_mask": &schema.Schema{
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"record_types": &schema.Schema{
+							Type:     schema.TypeList,
+							Optional: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func resourceVinylDNSZoneACLCreate(d *schema.ResourceData, meta interface{}) error {
+	zoneID := d.Get("zone_id").(string)
+	log.Printf("[INFO] Creating vinyldns zone ACL: %s", zoneID)
+	d.SetId(zoneID)
+
+	err := updateZoneACL(d, meta, expandACLRules(d.Get("rule").([]interface{})), d.Timeout(schema.TimeoutCreate))
+	if err != nil {
+		return err
+	}
+
+	return resourceVinylDNSZoneACLRead(d, meta)
+}
+
+func resourceVinylDNSZoneACLRead(d *schema.ResourceData, meta interface{}) error {
+	log.Printf("[INFO] Reading vinyldns zone ACL: %s", d.Id())
+	z, err := meta.(*Config).Client.Zone(d.Id())
+	if err != nil {
+		return zoneAccessError(err, d.Id())
+	}
+
+	rules := []vinyldns.ACLRule{}
+	if z.ACL != nil {
+		rules = z.ACL.Rules
+	}
+
+	d.Set("zone_id", z.ID)
+
+	return d.Set("rule", flattenACLRules(rules))
+}
+
+func resourceVinylDNSZoneACLUpdate(d *schema.ResourceData, meta interface{}) error {
+	log.Printf("[INFO] Updating vinyldns zone ACL: %s", d.Id())
+	err := updateZoneACL(d, meta, expandACLRules(d.Get("rule").([]interface{})), d.Timeout(schema.TimeoutUpdate))
+	if err != nil {
+		return err
+	}
+
+	return resourceVinylDNSZoneACLRead(d, meta)
+}
+
+func resourceVinylDNSZoneACLDelete(d *schema.ResourceData, meta interface{}) error {
+	log.Printf("[INFO] Deleting vinyldns zone ACL: %s", d.Id())
+	err := updateZoneACL(d, meta, []vinyldns.ACLRule{}, d.Timeout(schema.TimeoutDelete))
+	if err != nil {
+		return err
+	}
+
+	d.SetId("")
+
+	return nil
+}
+
+// updateZoneACL replaces the zone's ACL rules, leaving the rest of the zone as
+// vinyldns has it, and waits for the change to be applied.
+func updateZoneACL(d *schema.ResourceData, meta interface{}, rules []vinyldns.ACLRule, timeout time.Duration) error {
+	client := meta.(*Config).Client
+	z, err := client.Zone(d.Id())
+	if err != nil {
+		return err
+	}
+
+	z.ACL = &vinyldns.ZoneACL{Rules: rules}
+
+	change, err := client.ZoneUpdate(d.Id(), &z)
+	if err != nil {
+		return err
+	}
+
+	return waitUntilZoneChangeDeployed(d, meta, change.ID, timeout)
+}
+
+func expandACLRules(rules []interface{}) []vinyldns.ACLRule {
+	expanded := []vinyldns.ACLRule{}
+
+	for _, v := range rules {
+		rule := v.(map[string]interface{})
+		recordTypes := []string{}
+		for _, t := range rule["record_types"].([]interface{}) {
+			recordTypes = append(recordTypes, t.(string))
+		}
+
+		expanded = append(expanded, vinyldns.ACLRule{
+			AccessLevel: rule["access_level"].(string),
+			Description: rule["description"].(string),
+			UserID:      rule["user_id"].(string),
+			GroupID:     rule["group_id"].(string),
+			RecordMask:  rule["record_mask"].(string),
+			RecordTypes: recordTypes,
+		})
+	}
+
+	return expanded
+}
+
+func flattenACLRules(rules []vinyldns.ACLRule) []interface{} {
+	flattened := []interface{}{}
+
+	for _, rule := range rules {
+		recordTypes := []interface{}{}
+		for _, t := range rule.RecordTypes {
+			recordTypes = append(recordTypes, t)
+		}
+
+		flattened = append(flattened, map[string]interface{}{
+			"access_level": rule.AccessLevel,
+			"description":  rule.Description,
+			"user_id":      rule.UserID,
+			"group_id":     rule.GroupID,
+			"record_mask":  rule.RecordMask,
+			"record_types": recordTypes,
+		})
+	}
+
+	return flattened
+}
+
+// aclAccessLevels are the access levels vinyldns ACL rules grant, from least
+// to most access.
+var aclAccessLevels = []string{"NoAccess", "Read", "Write", "Delete"}
+
+// validateAccessLevel ensures the value is one of aclAccessLevels.
+func validateAccessLevel(v interface{}, k string) (ws []string, es []error) {
+	value := v.(string)
+
+	for _, level := range aclAccessLevels {
+		if value == level {
+			return
+		}
+	}
+
+	es = append(es, fmt.Errorf("%q must be one of %s; got %q", k, strings.Join(aclAccessLevels, ", "), value))
+
+	return
+}
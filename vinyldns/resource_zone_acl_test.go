@@ -0,0 +1,155 @@
+/*
+Copyright 2018 Comcast Cable Communications Management, LLC
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vinyldns
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/vinyldns/go-vinyldns/vinyldns"
+)
+
+func TestAccVinylDNSZoneACLOrdering(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccVinylDNSZoneDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: fmt.Sprintf(testAccVinylDNSZoneACLConfig, "Read", "Write"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckVinylDNSZoneACLOrder("vinyldns_zone_acl.test", "Read", "Write"),
+					resource.TestCheckResourceAttr("vinyldns_zone_acl.test", "rule.0.access_level", "Read"),
+				),
+			},
+			resource.TestStep{
+				Config: fmt.Sprintf(testAccVinylDNSZoneACLConfig, "Write", "Read"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckVinylDNSZoneACLOrder("vinyldns_zone_acl.test", "Write", "Read"),
+					resource.TestCheckResourceAttr("vinyldns_zone_acl.test", "rule.0.access_level", "Write"),
+				),
+			},
+		},
+	})
+}
+
+func TestExpandFlattenACLRules(t *testing.T) {
+	rules := []vinyldns.ACLRule{
+		vinyldns.ACLRule{AccessLevel: "Write", GroupID: "group-id", RecordMask: "www.*", RecordTypes: []string{"A", "AAAA"}},
+		vinyldns.ACLRule{AccessLevel: "Read", UserID: "user-id", Description: "auditors", RecordTypes: []string{}},
+	}
+
+	if roundTripped := expandACLRules(flattenACLRules(rules)); !reflect.DeepEqual(roundTripped, rules) {
+		t.Errorf("expected rules to survive a round trip in order; got %#v", roundTripped)
+	}
+}
+
+func TestExpandACLRulesReadOnlyTXT(t *testing.T) {
+	rules := expandACLRules([]interface{}{
+		map[string]interface{}{
+			"access_level": "Read",
+			"description":  "",
+			"user_id":      "",
+			"group_id":     "auditors-id",
+			"record_mask":  "^_acme-challenge\\..*",
+			"record_types": []interface{}{"TXT"},
+		},
+	})
+
+	expected := []vinyldns.ACLRule{
+		vinyldns.ACLRule{
+			AccessLevel: "Read",
+			GroupID:     "auditors-id",
+			RecordMask:  "^_acme-challenge\\..*",
+			RecordTypes: []string{"TXT"},
+		},
+	}
+	if !reflect.DeepEqual(rules, expected) {
+		t.Errorf("expected %#v; got %#v", expected, rules)
+	}
+}
+
+func TestValidateAccessLevel(t *testing.T) {
+	for _, v := range []string{"NoAccess", "Read", "Write", "Delete"} {
+		if _, es := validateAccessLevel(v, "access_level"); len(es) != 0 {
+			t.Errorf("expected %q to be valid; got %v", v, es)
+		}
+	}
+
+	for _, v := range []string{"", "read", "Admin", "ReadWrite"} {
+		if _, es := validateAccessLevel(v, "access_level"); len(es) == 0 {
+			t.Errorf("expected %q to be invalid", v)
+		}
+	}
+}
+
+// testAccCheckVinylDNSZoneACLOrder checks vinyldns holds the zone's ACL rules
+// with the given access levels, in order.
+func testAccCheckVinylDNSZoneACLOrder(n string, accessLevels ...string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found %s", n)
+		}
+
+		z, err := testAccProvider.Meta().(*Config).Client.Zone(rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+
+		if z.ACL == nil || len(z.ACL.Rules) != len(accessLevels) {
+			return fmt.Errorf("expected %d ACL rules; got %#v", len(accessLevels), z.ACL)
+		}
+
+		for i, level := range accessLevels {
+			if z.ACL.Rules[i].AccessLevel != level {
+				return fmt.Errorf("expected ACL rule %d to have access level %s; got %s", i, level, z.ACL.Rules[i].AccessLevel)
+			}
+		}
+
+		return nil
+	}
+}
+
+const testAccVinylDNSZoneACLConfig = `
+resource "vinyldns_group" "test_group" {
+	name = "terraformtestgroup"
+	description = "some description"
+	email = "tftest@tf.com"
+}
+
+resource "vinyldns_zone" "test_zone" {
+	name = "system-test."
+	email = "foo@bar.com"
+	admin_group_id = "${vinyldns_group.test_group.id}"
+	depends_on = [
+		"vinyldns_group.test_group"
+	]
+}
+
+resource "vinyldns_zone_acl" "test" {
+	zone_id = "${vinyldns_zone.test_zone.id}"
+	rule {
+		access_level = "%s"
+		group_id = "${vinyldns_group.test_group.id}"
+		record_mask = "www.*"
+	}
+	rule {
+		access_level = "%s"
+		group_id = "${vinyldns_group.test_group.id}"
+		record_types = ["A"]
+	}
+}`
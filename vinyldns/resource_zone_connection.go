@@ -0,0 +1,152 @@
+/*
+Copyright 2018 Comcast Cable Communications Management, LLC
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vinyldns
+
+import (
+	"log"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/vinyldns/go-vinyldns/vinyldns"
+)
+
+func resourceVinylDNSZoneConnection() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceVinylDNSZoneConnectionCreate,
+		Read:   resourceVinylDNSZoneConnectionRead,
+		Update: resourceVinylDNSZoneConnectionUpdate,
+		Delete: resourceVinylDNSZoneConnectionDelete,
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(30 * time.Minute),
+			Update: schema.DefaultTimeout(30 * time.Minute),
+			Delete: schema.DefaultTimeout(30 * time.Minute),
+		},
+
+		Schema: map[string]*schema.Schema{
+			// a zone has exactly one pair of connections, so they're new ones for a new zone
+			"zone_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"zone_connection":     connectionSchema(),
+			"transfer_connection": connectionSchema(),
+		},
+	}
+}
+
+func resourceVinylDNSZoneConnectionCreate(d *schema.ResourceData, meta interface{}) error {
+	zoneID := d.Get("zone_id").(string)
+	log.Printf("[INFO] Creating vinyldns zone connection: %s", zoneID)
+	d.SetId(zoneID)
+
+	err := updateZoneConnections(d, meta,
+		expandZoneConnection(d.Get("zone_connection").([]interface{})),
+		expandZoneConnection(d.Get("transfer_connection").([]interface{})),
+		d.Timeout(schema.TimeoutCreate))
+	if err != nil {
+		return err
+	}
+
+	return resourceVinylDNSZoneConnectionRead(d, meta)
+}
+
+func resourceVinylDNSZoneConnectionRead(d *schema.ResourceData, meta interface{}) error {
+	log.Printf("[INFO] Reading vinyldns zone connection: %s", d.Id())
+	z, err := meta.(*Config).Client.Zone(d.Id())
+	if err != nil {
+		return zoneAccessError(err, d.Id())
+	}
+
+	d.Set("zone_id", z.ID)
+
+	// vinyldns doesn't give keys back, so the configured ones are kept
+	if err := d.Set("zone_connection", flattenZoneConnection(z.Connection, d.Get("zone_connection.0.key").(string))); err != nil {
+		return err
+	}
+
+	return d.Set("transfer_connection", flattenZoneConnection(z.TransferConnection, d.Get("transfer_connection.0.key").(string)))
+}
+
+func resourceVinylDNSZoneConnectionUpdate(d *schema.ResourceData, meta interface{}) error {
+	log.Printf("[INFO] Updating vinyldns zone connection: %s", d.Id())
+	err := updateZoneConnections(d, meta,
+		expandZoneConnection(d.Get("zone_connection").([]interface{})),
+		expandZoneConnection(d.Get("transfer_connection").([]interface{})),
+		d.Timeout(schema.TimeoutUpdate))
+	if err != nil {
+		return err
+	}
+
+	return resourceVinylDNSZoneConnectionRead(d, meta)
+}
+
+func resourceVinylDNSZoneConnectionDelete(d *schema.ResourceData, meta interface{}) error {
+	log.Printf("[INFO] Deleting vinyldns zone connection: %s", d.Id())
+	err := updateZoneConnections(d, meta, nil, nil, d.Timeout(schema.TimeoutDelete))
+	if err != nil {
+		return err
+	}
+
+	d.SetId("")
+
+	return nil
+}
+
+// updateZoneConnections replaces the zone's connections, leaving the rest of
+// the zone as vinyldns has it, and waits for the change to be applied. A nil
+// connection returns the zone to vinyldns's default connection.
+func updateZoneConnections(d *schema.ResourceData, meta interface{}, connection, transfer *vinyldns.ZoneConnection, timeout time.Duration) error {
+	client := meta.(*Config).Client
+	z, err := client.Zone(d.Id())
+	if err != nil {
+		return err
+	}
+
+	z.Connection = connection
+	z.TransferConnection = transfer
+
+	change, err := client.ZoneUpdate(d.Id(), &z)
+	if err != nil {
+		return err
+	}
+
+	return waitUntilZoneChangeDeployed(d, meta, change.ID, timeout)
+}
+
+func expandZoneConnection(connections []interface{}) *vinyldns.ZoneConnection {
+	if len(connections) == 0 || connections[0] == nil {
+		return nil
+	}
+
+	c := connections[0].(map[string]interface{})
+
+	return &vinyldns.ZoneConnection{
+		Name:          c["name"].(string),
+		Key:           c["key"].(string),
+		KeyName:       c["key_name"].(string),
+		PrimaryServer: c["primary_server"].(string),
+	}
+}
+
+// flattenZoneConnection returns the connection as read back from vinyldns,
+// along with the given key.
+func flattenZoneConnection(c *vinyldns.ZoneConnection, key string) []interface{} {
+	flattened := flattenConnectionMetadata(c)
+	for _, v := range flattened {
+		v.(map[string]interface{})["key"] = key
+	}
+
+	return flattened
+}
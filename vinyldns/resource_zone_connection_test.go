@@ -0,0 +1,114 @@
+/*
+Copyright 2018 Comcast Cable Communications Management, LLC
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vinyldns
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/vinyldns/go-vinyldns/vinyldns"
+)
+
+func TestAccVinylDNSZoneConnectionBasic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccVinylDNSZoneDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: fmt.Sprintf(testAccVinylDNSZoneConnectionConfig, "123"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckVinylDNSZoneConnectionServer("vinyldns_zone_connection.test", "127.0.0.1"),
+					resource.TestCheckResourceAttr("vinyldns_zone_connection.test", "zone_connection.0.key_name", "vinyldns."),
+					resource.TestCheckResourceAttr("vinyldns_zone_connection.test", "zone_connection.0.key", "123"),
+				),
+			},
+			// rotating the key updates the connection in place
+			resource.TestStep{
+				Config: fmt.Sprintf(testAccVinylDNSZoneConnectionConfig, "456"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckVinylDNSZoneConnectionServer("vinyldns_zone_connection.test", "127.0.0.1"),
+					resource.TestCheckResourceAttr("vinyldns_zone_connection.test", "zone_connection.0.key", "456"),
+				),
+			},
+		},
+	})
+}
+
+func TestExpandFlattenZoneConnection(t *testing.T) {
+	if c := expandZoneConnection([]interface{}{}); c != nil {
+		t.Errorf("expected no connection when none is configured; got %#v", c)
+	}
+
+	c := &vinyldns.ZoneConnection{
+		Name:          "vinyldns.",
+		Key:           "123",
+		KeyName:       "vinyldns.",
+		PrimaryServer: "127.0.0.1",
+	}
+
+	if roundTripped := expandZoneConnection(flattenZoneConnection(c, "123")); !reflect.DeepEqual(roundTripped, c) {
+		t.Errorf("expected the connection to survive a round trip; got %#v", roundTripped)
+	}
+}
+
+// testAccCheckVinylDNSZoneConnectionServer checks vinyldns holds the zone's
+// connection with the given primary server.
+func testAccCheckVinylDNSZoneConnectionServer(n, primaryServer string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found %s", n)
+		}
+
+		z, err := testAccProvider.Meta().(*Config).Client.Zone(rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+
+		if z.Connection == nil || z.Connection.PrimaryServer != primaryServer {
+			return fmt.Errorf("expected a connection to %s; got %#v", primaryServer, z.Connection)
+		}
+
+		return nil
+	}
+}
+
+const testAccVinylDNSZoneConnectionConfig = `
+resource "vinyldns_group" "test_group" {
+	name = "terraformtestgroup"
+	description = "some description"
+	email = "tftest@tf.com"
+}
+
+resource "vinyldns_zone" "test_zone" {
+	name = "system-test."
+	email = "foo@bar.com"
+	admin_group_id = "${vinyldns_group.test_group.id}"
+	depends_on = [
+		"vinyldns_group.test_group"
+	]
+}
+
+resource "vinyldns_zone_connection" "test" {
+	zone_id = "${vinyldns_zone.test_zone.id}"
+	zone_connection {
+		name = "vinyldns."
+		key_name = "vinyldns."
+		key = "%s"
+		primary_server = "127.0.0.1"
+	}
+}`
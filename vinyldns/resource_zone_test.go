@@ -13,15 +13,88 @@ limitations under the License.
 package vinyldns
 
 import (
+	"errors"
 	"fmt"
 	"log"
+	"net/http"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/terraform/helper/resource"
 	"github.com/hashicorp/terraform/terraform"
 	"github.com/vinyldns/go-vinyldns/vinyldns"
 )
 
+func init() {
+	resource.AddTestSweepers("vinyldns_zone", &resource.Sweeper{
+		Name:         "vinyldns_zone",
+		Dependencies: []string{"vinyldns_record_set"},
+		F:            testSweepVinylDNSZones,
+	})
+}
+
+// testSweepVinylDNSZones deletes the zones administered by a terraformtest
+// group. The tests' zones have to be ones the vinyldns test backend serves,
+// such as system-test., so their names can't carry the prefix themselves.
+func testSweepVinylDNSZones(region string) error {
+	client := sweeperClient()
+	groups, err := client.GroupsListAll(vinyldns.ListFilter{})
+	if err != nil {
+		return fmt.Errorf("Error listing groups to sweep zones: %s", err)
+	}
+
+	testGroups := map[string]bool{}
+	for _, g := range groups {
+		if strings.HasPrefix(g.Name, "terraformtest") {
+			testGroups[g.ID] = true
+		}
+	}
+
+	zones, err := client.ZonesListAll(vinyldns.ListFilter{})
+	if err != nil {
+		return fmt.Errorf("Error listing zones to sweep: %s", err)
+	}
+
+	deleted := []vinyldns.Zone{}
+	for _, z := range zones {
+		if !testGroups[z.AdminGroupID] {
+			continue
+		}
+
+		log.Printf("[INFO] sweeping zone %s", z.Name)
+		if _, err := client.ZoneDelete(z.ID); err != nil {
+			return fmt.Errorf("Error sweeping zone %s: %s", z.ID, err)
+		}
+		deleted = append(deleted, z)
+	}
+
+	// zones are deleted asynchronously, and their admin groups, swept next,
+	// can't be deleted until they're gone
+	for _, z := range deleted {
+		stateConf := &resource.StateChangeConf{
+			Pending: []string{"Pending"},
+			Target:  []string{"Deleted"},
+			Refresh: func() (interface{}, string, error) {
+				exists, err := client.ZoneExists(z.ID)
+				if err != nil || exists {
+					return z, "Pending", err
+				}
+
+				return z, "Deleted", nil
+			},
+			Timeout:    5 * time.Minute,
+			MinTimeout: 2 * time.Second,
+		}
+
+		if _, err := stateConf.WaitForState(); err != nil {
+			return fmt.Errorf("Error waiting for zone %s to be swept: %s", z.ID, err)
+		}
+	}
+
+	return nil
+}
+
 func TestAccVinylDNSZoneBasic(t *testing.T) {
 	resource.Test(t, resource.TestCase{
 		PreCheck:     func() { testAccPreCheck(t) },
@@ -34,14 +107,55 @@ func TestAccVinylDNSZoneBasic(t *testing.T) {
 					testAccCheckVinylDNSZoneExists("vinyldns_zone.test_zone"),
 					resource.TestCheckResourceAttr("vinyldns_zone.test_zone", "name", "system-test."),
 					resource.TestCheckResourceAttr("vinyldns_zone.test_zone", "email", "foo@bar.com"),
+					resource.TestCheckResourceAttr("vinyldns_zone.test_zone", "default_record_ttl", "300"),
 				),
 			},
 		},
 	})
 }
 
+func TestZoneAccessError(t *testing.T) {
+	meta, closeServer := testMeta(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`"User ok does not have access to zone zone-id"`))
+	})
+	defer closeServer()
+
+	_, err := meta.Client.Zone("zone-id")
+	err = zoneAccessError(err, "zone-id")
+	if err == nil || !strings.Contains(err.Error(), "not authorized to access zone zone-id") || !strings.Contains(err.Error(), "ACL rules") {
+		t.Errorf("expected an error naming the zone and suggesting its access be checked; got %v", err)
+	}
+
+	notFound := &vinyldns.Error{ResponseCode: http.StatusNotFound}
+	if err := zoneAccessError(notFound, "zone-id"); err != notFound {
+		t.Errorf("expected a 404 to be returned unchanged; got %v", err)
+	}
+
+	other := errors.New("boom")
+	if err := zoneAccessError(other, "zone-id"); err != other {
+		t.Errorf("expected other errors to be returned unchanged; got %v", err)
+	}
+}
+
+func TestValidateEmail(t *testing.T) {
+	valid := []string{"foo@bar.com", "dns-ops@example.co.uk"}
+	for _, v := range valid {
+		if _, errs := validateEmail(v, "email"); len(errs) != 0 {
+			t.Errorf("expected %s to be valid; got %v", v, errs)
+		}
+	}
+
+	invalid := []string{"", "foo", "foo@", "foo bar@baz.com", "Foo <foo@bar.com>"}
+	for _, v := range invalid {
+		if _, errs := validateEmail(v, "email"); len(errs) == 0 {
+			t.Errorf("expected %q to be invalid", v)
+		}
+	}
+}
+
 func testAccVinylDNSZoneDestroy(s *terraform.State) error {
-	client := testAccProvider.Meta().(*vinyldns.Client)
+	client := testAccProvider.Meta().(*Config).Client
 
 	for _, rs := range s.RootModule().Resources {
 		log.Printf("[INFO] testing zone destruction; rs.Type: %s", rs.Type)
@@ -74,7 +188,7 @@ func testAccCheckVinylDNSZoneExists(n string) resource.TestCheckFunc {
 			return fmt.Errorf("No Zone ID is set")
 		}
 
-		client := testAccProvider.Meta().(*vinyldns.Client)
+		client := testAccProvider.Meta().(*Config).Client
 
 		readZone, err := client.Zone(rs.Primary.ID)
 		if err != nil {
@@ -99,6 +213,7 @@ resource "vinyldns_zone" "test_zone" {
 	name = "system-test."
 	email = "foo@bar.com"
 	admin_group_id = "${vinyldns_group.test_group.id}"
+	default_record_ttl = 300
 	depends_on = [
 		"vinyldns_group.test_group"
 	]
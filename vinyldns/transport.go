@@ -0,0 +1,359 @@
+/*
+Copyright 2018 Comcast Cable Communications Management, LLC
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vinyldns
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// headerTransport is an http.RoundTripper that sets a fixed group of headers
+// on every request before handing it to the wrapped RoundTripper. Headers it
+// sets replace any the go-vinyldns client already set under the same name.
+type headerTransport struct {
+	headers http.Header
+	next    http.RoundTripper
+}
+
+func (t *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// a RoundTripper must not modify the request it's given
+	r := new(http.Request)
+	*r = *req
+	r.Header = make(http.Header, len(req.Header)+len(t.headers))
+	for k, v := range req.Header {
+		r.Header[k] = v
+	}
+	for k, v := range t.headers {
+		r.Header[k] = v
+	}
+
+	return t.next.RoundTrip(r)
+}
+
+// userAgentTransport is an http.RoundTripper that appends a suffix to the
+// User-Agent of every request, so that vinyldns operators can tell apart the
+// workspaces and teams the requests come from.
+type userAgentTransport struct {
+	suffix string
+	next   http.RoundTripper
+}
+
+func (t *userAgentTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.suffix == "" {
+		return t.next.RoundTrip(req)
+	}
+
+	// a RoundTripper must not modify the request it's given
+	r := new(http.Request)
+	*r = *req
+	r.Header = make(http.Header, len(req.Header))
+	for k, v := range req.Header {
+		r.Header[k] = v
+	}
+	r.Header.Set("User-Agent", strings.TrimSpace(req.Header.Get("User-Agent")+" "+t.suffix))
+
+	return t.next.RoundTrip(r)
+}
+
+// userAgentSuffix returns the User-Agent comment identifying the workspace and
+// team, either of which may be empty, that requests are made on behalf of.
+func userAgentSuffix(workspaceID, team string) string {
+	tags := []string{}
+	if workspaceID != "" {
+		tags = append(tags, "workspace_id="+workspaceID)
+	}
+	if team != "" {
+		tags = append(tags, "team="+team)
+	}
+
+	if len(tags) == 0 {
+		return ""
+	}
+
+	return "(" + strings.Join(tags, "; ") + ")"
+}
+
+// rateLimitMaxRetries is how many times a throttled request is retried before
+// the 429 response is handed back to the go-vinyldns client.
+const rateLimitMaxRetries = 3
+
+// rateLimitDefaultWait is how long a throttled or refused request waits before
+// it's retried when the response has no usable Retry-After header.
+const rateLimitDefaultWait = 5 * time.Second
+
+// rateLimitTransport is an http.RoundTripper that retries requests throttled
+// with a 429, waiting as long as the response's Retry-After header asks.
+type rateLimitTransport struct {
+	metrics *providerMetrics
+	next    http.RoundTripper
+}
+
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		resp, err := t.next.RoundTrip(req)
+		if err != nil || resp.StatusCode != http.StatusTooManyRequests {
+			return resp, err
+		}
+
+		remaining := resp.Header.Get("X-RateLimit-Remaining")
+		if remaining == "" {
+			remaining = "unknown"
+		}
+
+		// a request whose body can't be replayed can't be retried
+		if attempt == rateLimitMaxRetries || (req.Body != nil && req.GetBody == nil) {
+			log.Printf("[INFO] vinyldns throttled %s %s; remaining quota: %s", req.Method, req.URL.Path, remaining)
+			return resp, nil
+		}
+
+		wait := retryAfter(resp, time.Now())
+		log.Printf("[INFO] vinyldns throttled %s %s; remaining quota: %s; retrying in %s", req.Method, req.URL.Path, remaining, wait)
+		resp.Body.Close()
+		t.metrics.inc(metricAPIRetries)
+
+		req, err = rewind(req)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := sleep(req, wait); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// maintenanceRetryWindow is how long requests refused with a 503 keep being
+// retried before the provider gives up on vinyldns coming back.
+const maintenanceRetryWindow = 2 * time.Minute
+
+// maintenanceBodyLimit is how much of a 503's body is read to tell whether
+// vinyldns sent it because it's in maintenance or read-only mode.
+const maintenanceBodyLimit = 512
+
+// maintenanceTransport is an http.RoundTripper that retries requests vinyldns
+// refuses with a 503 while it's in maintenance or read-only mode, for a
+// bounded window. Once the window has passed, it fails with an error saying so
+// rather than hand back an opaque 503. A 503 whose body doesn't say vinyldns
+// is in maintenance, as from a proxy in front of it, is handed back as it is.
+type maintenanceTransport struct {
+	window  time.Duration
+	metrics *providerMetrics
+	next    http.RoundTripper
+}
+
+func (t *maintenanceTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	deadline := time.Now().Add(t.window)
+
+	for {
+		resp, err := t.next.RoundTrip(req)
+		if err != nil || resp.StatusCode != http.StatusServiceUnavailable {
+			return resp, err
+		}
+
+		detail, err := peekBody(resp, maintenanceBodyLimit)
+		if err != nil {
+			return nil, err
+		}
+
+		if !maintenanceRetryable(req.Method, detail) {
+			return resp, nil
+		}
+
+		wait := retryAfter(resp, time.Now())
+		if time.Now().Add(wait).After(deadline) || (req.Body != nil && req.GetBody == nil) {
+			return nil, maintenanceError(req, resp, t.window)
+		}
+
+		log.Printf("[INFO] vinyldns is unavailable for %s %s, likely for maintenance; retrying in %s", req.Method, req.URL.Path, wait)
+		resp.Body.Close()
+		t.metrics.inc(metricAPIRetries)
+
+		req, err = rewind(req)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := sleep(req, wait); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// maintenanceRetryable reports whether a request refused with a 503 whose body
+// starts with detail can be sent again. Reads can be whenever vinyldns says
+// it's in maintenance or read-only mode. Anything else, such as a POST that
+// creates a record set change, only when vinyldns says it's read-only, so the
+// request was refused rather than applied, and sending it again won't make a
+// second change.
+func maintenanceRetryable(method, detail string) bool {
+	detail = strings.ToLower(detail)
+	readOnly := strings.Contains(detail, "read-only") || strings.Contains(detail, "read only")
+
+	switch method {
+	case http.MethodGet, http.MethodHead:
+		return readOnly || strings.Contains(detail, "maintenance")
+	default:
+		return readOnly
+	}
+}
+
+// peekBody returns up to the first n bytes of the response's body, leaving the
+// body as it was for whoever reads it next.
+func peekBody(resp *http.Response, n int64) (string, error) {
+	prefix, err := ioutil.ReadAll(io.LimitReader(resp.Body, n))
+	if err != nil {
+		resp.Body.Close()
+		return "", err
+	}
+
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(prefix), resp.Body), resp.Body}
+
+	return string(prefix), nil
+}
+
+// maintenanceError describes a request vinyldns kept refusing with a 503,
+// including the start of the response body for any detail it gives.
+func maintenanceError(req *http.Request, resp *http.Response, window time.Duration) error {
+	defer resp.Body.Close()
+
+	body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, maintenanceBodyLimit))
+	detail := strings.TrimSpace(string(body))
+	if detail == "" {
+		detail = resp.Status
+	}
+
+	return fmt.Errorf("vinyldns at %s is unavailable, most likely in maintenance or read-only mode, and was still refusing %s %s after %s; "+
+		"try again once it's back: %s", req.URL.Host, req.Method, req.URL.Path, window, detail)
+}
+
+// rewind returns a copy of req with a fresh body, so that it can be sent again.
+func rewind(req *http.Request) (*http.Request, error) {
+	if req.GetBody == nil {
+		return req, nil
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+
+	r := new(http.Request)
+	*r = *req
+	r.Body = body
+
+	return r, nil
+}
+
+// sleep waits for d, or until req is cancelled.
+func sleep(req *http.Request, d time.Duration) error {
+	select {
+	case <-time.After(d):
+		return nil
+	case <-req.Context().Done():
+		return req.Context().Err()
+	}
+}
+
+// retryAfter returns how long the response's Retry-After header, given either
+// in seconds or as an HTTP date, asks the client to wait.
+func retryAfter(resp *http.Response, now time.Time) time.Duration {
+	v := resp.Header.Get("Retry-After")
+
+	if seconds, err := strconv.Atoi(v); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second
+	}
+
+	if at, err := http.ParseTime(v); err == nil {
+		if wait := at.Sub(now); wait > 0 {
+			return wait
+		}
+
+		return 0
+	}
+
+	return rateLimitDefaultWait
+}
+
+// maxRedirects is how many redirects a single request may follow.
+const maxRedirects = 10
+
+// checkRedirect is the redirect policy of the provider's *http.Client. It only
+// follows redirects that stay on the configured host and keep the request as it
+// was: the request signature or bearer token would otherwise be sent to another
+// host, over plain http, or for a request other than the one it was made for.
+func checkRedirect(req *http.Request, via []*http.Request) error {
+	orig := via[0]
+
+	switch {
+	case len(via) >= maxRedirects:
+		return fmt.Errorf("vinyldns at %s redirected %s %s more than %d times", orig.URL.Host, orig.Method, orig.URL.Path, maxRedirects)
+	case !strings.EqualFold(req.URL.Hostname(), orig.URL.Hostname()):
+		return fmt.Errorf("vinyldns at %s redirected %s %s to another host, %s; not following it so that credentials aren't sent there. "+
+			"If vinyldns has moved, set host to its new address", orig.URL.Host, orig.Method, orig.URL.Path, req.URL.Host)
+	case orig.URL.Scheme == "https" && req.URL.Scheme != "https":
+		return fmt.Errorf("vinyldns at %s redirected %s %s from https to %s; not following it so that credentials aren't sent unencrypted",
+			orig.URL.Host, orig.Method, orig.URL.Path, req.URL.Scheme)
+	case req.Method != orig.Method:
+		return fmt.Errorf("vinyldns at %s redirected %s %s such that it would be resent as a %s; not following it. "+
+			"If vinyldns has moved, set host to its new address", orig.URL.Host, orig.Method, orig.URL.Path, req.Method)
+	}
+
+	log.Printf("[DEBUG] Following vinyldns redirect of %s %s to %s", orig.Method, orig.URL.Path, req.URL)
+
+	return nil
+}
+
+// httpClient returns the *http.Client the provider's go-vinyldns client uses,
+// layering on the provider's request customizations. extraHeaders are sent on
+// every request, such as for API gateways that route on them, and userAgent,
+// if not empty, is appended to every request's User-Agent. Retries are counted
+// in metrics, which may be nil.
+func httpClient(token string, extraHeaders map[string]string, userAgent string, metrics *providerMetrics) *http.Client {
+	headers := http.Header{}
+	for k, v := range extraHeaders {
+		headers.Set(k, v)
+	}
+
+	// the bearer token replaces the go-vinyldns request signature
+	if token != "" {
+		headers.Set("Authorization", "Bearer "+token)
+	}
+
+	return &http.Client{
+		CheckRedirect: checkRedirect,
+		Transport: &rateLimitTransport{
+			metrics: metrics,
+			next: &maintenanceTransport{
+				window:  maintenanceRetryWindow,
+				metrics: metrics,
+				next: &headerTransport{
+					headers: headers,
+					next: &userAgentTransport{
+						suffix: userAgent,
+						next:   http.DefaultTransport,
+					},
+				},
+			},
+		},
+	}
+}
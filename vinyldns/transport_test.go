@@ -0,0 +1,438 @@
+/*
+Copyright 2018 Comcast Cable Communications Management, LLC
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vinyldns
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHTTPClientToken(t *testing.T) {
+	var auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+	}))
+	defer server.Close()
+
+	req, err := http.NewRequest("GET", server.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Authorization", "AWS4-HMAC-SHA256 signature")
+
+	if _, err := httpClient("abc123", nil, "", nil).Do(req); err != nil {
+		t.Fatal(err)
+	}
+
+	if auth != "Bearer abc123" {
+		t.Errorf("expected bearer token Authorization header; got %s", auth)
+	}
+
+	if req.Header.Get("Authorization") != "AWS4-HMAC-SHA256 signature" {
+		t.Error("expected the original request's headers to be left unmodified")
+	}
+}
+
+func TestHTTPClientNoToken(t *testing.T) {
+	var auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+	}))
+	defer server.Close()
+
+	req, err := http.NewRequest("GET", server.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Authorization", "AWS4-HMAC-SHA256 signature")
+
+	if _, err := httpClient("", nil, "", nil).Do(req); err != nil {
+		t.Fatal(err)
+	}
+
+	if auth != "AWS4-HMAC-SHA256 signature" {
+		t.Errorf("expected the request signature to be sent unchanged; got %s", auth)
+	}
+}
+
+func TestHTTPClientRetriesThrottledRequests(t *testing.T) {
+	requests := 0
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		body, _ := ioutil.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+
+		if requests < 3 {
+			w.Header().Set("Retry-After", "0")
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+		}
+	}))
+	defer server.Close()
+
+	req, err := http.NewRequest("POST", server.URL, bytes.NewBufferString(`{"name":"foo"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := httpClient("", nil, "", nil).Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected the retried request to succeed; got %d", resp.StatusCode)
+	}
+
+	if requests != 3 {
+		t.Errorf("expected 3 requests; got %d", requests)
+	}
+
+	for _, b := range bodies {
+		if b != `{"name":"foo"}` {
+			t.Errorf("expected each attempt to send the full body; got %q", b)
+		}
+	}
+}
+
+func TestHTTPClientGivesUpOnThrottledRequests(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Retry-After", "0")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	resp, err := httpClient("", nil, "", nil).Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if resp.StatusCode != http.StatusTooManyRequests {
+		t.Errorf("expected the final 429 to be returned; got %d", resp.StatusCode)
+	}
+
+	if requests != rateLimitMaxRetries+1 {
+		t.Errorf("expected %d requests; got %d", rateLimitMaxRetries+1, requests)
+	}
+}
+
+func TestHTTPClientRetriesUnavailableRequests(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests < 3 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte("VinylDNS is down for maintenance"))
+		}
+	}))
+	defer server.Close()
+
+	resp, err := httpClient("", nil, "", nil).Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected the retried request to succeed; got %d", resp.StatusCode)
+	}
+
+	if requests != 3 {
+		t.Errorf("expected 3 requests; got %d", requests)
+	}
+}
+
+func TestHTTPClientDoesNotRetryOtherUnavailableRequests(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Retry-After", "0")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte("no healthy upstream"))
+	}))
+	defer server.Close()
+
+	resp, err := httpClient("", nil, "", nil).Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	body, _ := ioutil.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusServiceUnavailable || string(body) != "no healthy upstream" {
+		t.Errorf("expected the 503 to be returned as it was; got %d %q", resp.StatusCode, body)
+	}
+
+	if requests != 1 {
+		t.Errorf("expected 1 request; got %d", requests)
+	}
+}
+
+func TestHTTPClientRetriesWritesOnlyWhenReadOnly(t *testing.T) {
+	cases := []struct {
+		body     string
+		requests int
+	}{
+		{"VinylDNS is down for maintenance", 1},
+		{"VinylDNS is in read-only mode", 2},
+	}
+
+	for _, c := range cases {
+		requests := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			if requests < 2 {
+				w.Header().Set("Retry-After", "0")
+				w.WriteHeader(http.StatusServiceUnavailable)
+				w.Write([]byte(c.body))
+			}
+		}))
+
+		resp, err := httpClient("", nil, "", nil).Post(server.URL, "application/json", strings.NewReader(`{"name":"foo"}`))
+		server.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+
+		if requests != c.requests {
+			t.Errorf("expected %d requests for a POST refused with %q; got %d", c.requests, c.body, requests)
+		}
+	}
+}
+
+func TestMaintenanceTransportGivesUp(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "1")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte("VinylDNS is in read-only mode"))
+	}))
+	defer server.Close()
+
+	client := &http.Client{
+		Transport: &maintenanceTransport{
+			window: 10 * time.Millisecond,
+			next:   http.DefaultTransport,
+		},
+	}
+
+	_, err := client.Get(server.URL)
+	if err == nil {
+		t.Fatal("expected an error once vinyldns stayed unavailable")
+	}
+
+	if !strings.Contains(err.Error(), "maintenance") || !strings.Contains(err.Error(), "read-only mode") {
+		t.Errorf("expected the error to explain vinyldns is in maintenance; got %s", err)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2018, 10, 1, 12, 0, 0, 0, time.UTC)
+	cases := []struct {
+		header   string
+		expected time.Duration
+	}{
+		{"0", 0},
+		{"7", 7 * time.Second},
+		{now.Add(30 * time.Second).Format(http.TimeFormat), 30 * time.Second},
+		{now.Add(-30 * time.Second).Format(http.TimeFormat), 0},
+		{"", rateLimitDefaultWait},
+		{"soon", rateLimitDefaultWait},
+	}
+
+	for _, c := range cases {
+		resp := &http.Response{Header: http.Header{}}
+		resp.Header.Set("Retry-After", c.header)
+
+		if wait := retryAfter(resp, now); wait != c.expected {
+			t.Errorf("expected Retry-After %q to wait %s; got %s", c.header, c.expected, wait)
+		}
+	}
+}
+
+func TestHTTPClientExtraHeaders(t *testing.T) {
+	var route, auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route = r.Header.Get("X-API-Route")
+		auth = r.Header.Get("Authorization")
+	}))
+	defer server.Close()
+
+	req, err := http.NewRequest("GET", server.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Authorization", "AWS4-HMAC-SHA256 signature")
+
+	client := httpClient("", map[string]string{"X-API-Route": "vinyldns"}, "", nil)
+	if _, err := client.Do(req); err != nil {
+		t.Fatal(err)
+	}
+
+	if route != "vinyldns" {
+		t.Errorf("expected the extra header to be sent; got %q", route)
+	}
+
+	if auth != "AWS4-HMAC-SHA256 signature" {
+		t.Errorf("expected the request signature to be sent unchanged; got %s", auth)
+	}
+}
+
+func TestHTTPClientUserAgent(t *testing.T) {
+	var userAgent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userAgent = r.Header.Get("User-Agent")
+	}))
+	defer server.Close()
+
+	req, err := http.NewRequest("GET", server.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("User-Agent", "go-vinyldns")
+
+	if _, err := httpClient("", nil, userAgentSuffix("ws-123", "dns"), nil).Do(req); err != nil {
+		t.Fatal(err)
+	}
+
+	if userAgent != "go-vinyldns (workspace_id=ws-123; team=dns)" {
+		t.Errorf("expected the workspace and team to be appended to the User-Agent; got %q", userAgent)
+	}
+}
+
+func TestUserAgentSuffix(t *testing.T) {
+	cases := map[[2]string]string{
+		{"", ""}:       "",
+		{"ws-123", ""}: "(workspace_id=ws-123)",
+		{"", "dns"}:    "(team=dns)",
+	}
+
+	for tags, expected := range cases {
+		if got := userAgentSuffix(tags[0], tags[1]); got != expected {
+			t.Errorf("expected %q for workspace_id %q and team %q; got %q", expected, tags[0], tags[1], got)
+		}
+	}
+}
+
+func TestHTTPClientFollowsSameHostRedirects(t *testing.T) {
+	var auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/old" {
+			http.Redirect(w, r, "/new", http.StatusMovedPermanently)
+			return
+		}
+
+		auth = r.Header.Get("Authorization")
+	}))
+	defer server.Close()
+
+	resp, err := httpClient("abc123", nil, "", nil).Get(server.URL + "/old")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if resp.Request.URL.Path != "/new" {
+		t.Errorf("expected the redirect to be followed; ended at %s", resp.Request.URL.Path)
+	}
+
+	if auth != "Bearer abc123" {
+		t.Errorf("expected the bearer token to be kept on a same-host redirect; got %s", auth)
+	}
+}
+
+func TestHTTPClientRefusesCrossHostRedirects(t *testing.T) {
+	requests := 0
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+	}))
+	defer target.Close()
+
+	// same server, different host name
+	redirect := strings.Replace(target.URL, "127.0.0.1", "localhost", 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, redirect, http.StatusFound)
+	}))
+	defer server.Close()
+
+	_, err := httpClient("abc123", nil, "", nil).Get(server.URL)
+	if err == nil || !strings.Contains(err.Error(), "another host") {
+		t.Errorf("expected an error refusing the cross-host redirect; got %v", err)
+	}
+
+	if requests != 0 {
+		t.Errorf("expected no requests to the redirect target; got %d", requests)
+	}
+}
+
+func TestHTTPClientRefusesMethodChangingRedirects(t *testing.T) {
+	gets := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" {
+			gets++
+			return
+		}
+
+		http.Redirect(w, r, "/elsewhere", http.StatusFound)
+	}))
+	defer server.Close()
+
+	_, err := httpClient("", nil, "", nil).Post(server.URL, "application/json", bytes.NewBufferString("{}"))
+	if err == nil || !strings.Contains(err.Error(), "resent as a GET") {
+		t.Errorf("expected an error refusing to turn the POST into a GET; got %v", err)
+	}
+
+	if gets != 0 {
+		t.Errorf("expected the redirect not to be followed; got %d GETs", gets)
+	}
+}
+
+func TestCheckRedirect(t *testing.T) {
+	request := func(method, url string) *http.Request {
+		req, err := http.NewRequest(method, url, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return req
+	}
+
+	orig := request("GET", "http://vinyldns.example.com/zones")
+
+	if err := checkRedirect(request("GET", "https://vinyldns.example.com/zones"), []*http.Request{orig}); err != nil {
+		t.Errorf("expected an http to https redirect on the same host to be followed; got %s", err)
+	}
+
+	https := request("GET", "https://vinyldns.example.com/zones")
+	if err := checkRedirect(request("GET", "http://vinyldns.example.com/zones"), []*http.Request{https}); err == nil {
+		t.Error("expected an https to http redirect to be refused")
+	}
+
+	if err := checkRedirect(request("GET", "https://evil.example.com/zones"), []*http.Request{orig}); err == nil {
+		t.Error("expected a cross-host redirect to be refused")
+	}
+
+	via := []*http.Request{}
+	for i := 0; i < maxRedirects; i++ {
+		via = append(via, orig)
+	}
+	if err := checkRedirect(request("GET", "http://vinyldns.example.com/zones"), via); err == nil {
+		t.Error("expected a redirect loop to be stopped")
+	}
+}